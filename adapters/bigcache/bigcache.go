@@ -0,0 +1,84 @@
+// Package bigcache wraps allegro/bigcache as a low-GC mlcache.Cacher,
+// suitable for an L1 level holding many short-lived entries without GC
+// scan pressure.
+package bigcache
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/errs"
+)
+
+var _ mlcache.Cacher = &Cacher{}
+
+// Cacher wraps an *bigcache.BigCache as a mlcache.Cacher. BigCache evicts
+// on its own configured LifeWindow, so the expires passed into Put is not
+// enforced here; configure BigCache's own TTL through its Config instead.
+type Cacher struct {
+	bc *bigcache.BigCache
+}
+
+// NewCacher wraps an existing *bigcache.BigCache as a mlcache.Cacher.
+func NewCacher(bc *bigcache.BigCache) *Cacher {
+	return &Cacher{bc: bc}
+}
+
+// Get returns the cache item, if present
+func (c *Cacher) Get(key *mlcache.CacheKey) (*bytes.Buffer, time.Time, error) {
+
+	data, err := c.bc.Get(key.AsString())
+	if err != nil {
+		return nil, time.Now().UTC(), errs.Build(err, mlcache.GetError)
+	}
+
+	return bytes.NewBuffer(data), time.Now().UTC(), nil
+}
+
+// Put adds/updates a cache item
+func (c *Cacher) Put(key *mlcache.CacheKey, val *bytes.Buffer, expires time.Time) (mlcache.CacheStatus, error) {
+
+	data := []byte{}
+	if val != nil {
+		data = val.Bytes()
+	}
+
+	if err := c.bc.Set(key.AsString(), data); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// Del deletes the key from the cache
+func (c *Cacher) Del(key *mlcache.CacheKey) (mlcache.CacheStatus, error) {
+
+	if err := c.bc.Delete(key.AsString()); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.DelError)
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// IsPresent returns true if the key is present
+func (c *Cacher) IsPresent(key *mlcache.CacheKey) (mlcache.CacheStatus, error) {
+
+	if _, err := c.bc.Get(key.AsString()); err != nil {
+		return mlcache.CacheStatusFailure, nil
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// Flush clears all keys from the cache
+func (c *Cacher) Flush() error {
+
+	if err := c.bc.Reset(); err != nil {
+		return errs.Build(err, mlcache.FlushError)
+	}
+
+	return nil
+}
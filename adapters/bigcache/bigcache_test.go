@@ -0,0 +1,23 @@
+package bigcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/cachertest"
+)
+
+func TestBigCacheCacherContract(t *testing.T) {
+
+	cachertest.RunContractTests(t, func() mlcache.Cacher {
+		bc, err := bigcache.New(context.Background(), bigcache.DefaultConfig(10*time.Minute))
+		if err != nil {
+			t.Fatalf("problem creating bigcache instance: %v\n", err)
+		}
+		return NewCacher(bc)
+	})
+}
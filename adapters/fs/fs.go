@@ -0,0 +1,264 @@
+// Package fs provides a filesystem-backed mlcache.Cacher, suitable as an
+// L3 persistent layer (e.g. blobCacheStoreType=fs in examples).
+package fs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/errs"
+)
+
+var _ mlcache.Cacher = &Cacher{}
+var _ mlcache.BatchGetter = &Cacher{}
+
+// Cacher stores each key's value at a content-addressed path under
+// baseDir, with a sidecar ".ttl" file holding the absolute expiry.
+type Cacher struct {
+	baseDir string
+	mtx     sync.Mutex
+}
+
+// NewCacher creates a filesystem Cacher rooted at baseDir, creating it if
+// it does not already exist.
+func NewCacher(baseDir string) (*Cacher, error) {
+
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, errs.Build(err, mlcache.PutError)
+	}
+
+	return &Cacher{baseDir: baseDir}, nil
+}
+
+// Get returns the cache item, if present and not expired
+func (c *Cacher) Get(key *mlcache.CacheKey) (*bytes.Buffer, time.Time, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	path := c.pathFor(key.AsString())
+
+	expires, err := c.readTTL(path)
+	if err != nil {
+		return nil, time.Now().UTC(), errs.Build(err, mlcache.GetError)
+	}
+	if !expires.IsZero() && expires.Before(time.Now().UTC()) {
+		c.removeLocked(path)
+		return nil, time.Now().UTC(), errs.New(mlcache.GetError)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Now().UTC(), errs.Build(err, mlcache.GetError)
+	}
+
+	return bytes.NewBuffer(data), expires, nil
+}
+
+// GetMulti implements mlcache.BatchGetter. Requested keys land in at most
+// 256 shard directories (the first two hex characters of their content
+// hash), so instead of stat-ing each key's file individually, the keys are
+// sorted by path to bring same-shard keys adjacent, each run of adjacent
+// keys sharing a shard is serviced with a single os.ReadDir, and results
+// are scattered back to the caller's key order via the index recorded for
+// each request.
+func (c *Cacher) GetMulti(keys []*mlcache.CacheKey) ([]*bytes.Buffer, []time.Time, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	vals := make([]*bytes.Buffer, len(keys))
+	ttls := make([]time.Time, len(keys))
+
+	type request struct {
+		idx  int
+		name string
+		path string
+	}
+
+	reqs := make([]request, len(keys))
+	for i, key := range keys {
+		name := hashName(key.AsString())
+		reqs[i] = request{idx: i, name: name, path: filepath.Join(c.baseDir, name[:2], name)}
+	}
+
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].path < reqs[j].path })
+
+	for i := 0; i < len(reqs); {
+		shard := filepath.Dir(reqs[i].path)
+
+		j := i + 1
+		for j < len(reqs) && filepath.Dir(reqs[j].path) == shard {
+			j++
+		}
+
+		entries, err := os.ReadDir(shard)
+		if err != nil {
+			i = j
+			continue
+		}
+		present := make(map[string]struct{}, len(entries))
+		for _, e := range entries {
+			present[e.Name()] = struct{}{}
+		}
+
+		for _, r := range reqs[i:j] {
+			if _, ok := present[r.name]; !ok {
+				continue
+			}
+
+			expires, err := c.readTTL(r.path)
+			if err != nil {
+				continue
+			}
+			if !expires.IsZero() && expires.Before(time.Now().UTC()) {
+				c.removeLocked(r.path)
+				continue
+			}
+
+			data, err := os.ReadFile(r.path)
+			if err != nil {
+				continue
+			}
+
+			vals[r.idx] = bytes.NewBuffer(data)
+			ttls[r.idx] = expires
+		}
+
+		i = j
+	}
+
+	return vals, ttls, nil
+}
+
+// Put adds/updates a cache item
+func (c *Cacher) Put(key *mlcache.CacheKey, val *bytes.Buffer, expires time.Time) (mlcache.CacheStatus, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	path := c.pathFor(key.AsString())
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+	}
+
+	data := []byte{}
+	if val != nil {
+		data = val.Bytes()
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+	}
+	if err := c.writeTTL(path, expires); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// Del deletes the key from the cache
+func (c *Cacher) Del(key *mlcache.CacheKey) (mlcache.CacheStatus, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.removeLocked(c.pathFor(key.AsString()))
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// IsPresent returns true if the key is present and not expired
+func (c *Cacher) IsPresent(key *mlcache.CacheKey) (mlcache.CacheStatus, error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	path := c.pathFor(key.AsString())
+
+	if _, err := os.Stat(path); err != nil {
+		return mlcache.CacheStatusFailure, nil
+	}
+
+	if expires, err := c.readTTL(path); err == nil && !expires.IsZero() && expires.Before(time.Now().UTC()) {
+		c.removeLocked(path)
+		return mlcache.CacheStatusFailure, nil
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// Flush clears all keys from the cache
+func (c *Cacher) Flush() error {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if err := os.RemoveAll(c.baseDir); err != nil {
+		return errs.Build(err, mlcache.FlushError)
+	}
+
+	return os.MkdirAll(c.baseDir, 0o755)
+}
+
+// pathFor returns the content-addressed file path for key, sharded into a
+// two-character directory prefix to keep any single directory small
+func (c *Cacher) pathFor(key string) string {
+
+	name := hashName(key)
+
+	return filepath.Join(c.baseDir, name[:2], name)
+}
+
+// hashName returns the content-addressed file name for key
+func hashName(key string) string {
+
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cacher) ttlPath(path string) string {
+	return path + ".ttl"
+}
+
+func (c *Cacher) readTTL(path string) (time.Time, error) {
+
+	data, err := os.ReadFile(c.ttlPath(path))
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	unixNano, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, unixNano).UTC(), nil
+}
+
+func (c *Cacher) writeTTL(path string, expires time.Time) error {
+
+	if expires.IsZero() {
+		os.Remove(c.ttlPath(path))
+		return nil
+	}
+
+	return os.WriteFile(c.ttlPath(path), []byte(strconv.FormatInt(expires.UnixNano(), 10)), 0o644)
+}
+
+func (c *Cacher) removeLocked(path string) {
+	os.Remove(path)
+	os.Remove(c.ttlPath(path))
+}
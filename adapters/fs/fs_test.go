@@ -0,0 +1,51 @@
+package fs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/cachertest"
+)
+
+func TestFsCacherContract(t *testing.T) {
+
+	cachertest.RunContractTests(t, func() mlcache.Cacher {
+		c, err := NewCacher(t.TempDir())
+		if err != nil {
+			t.Fatalf("problem creating fs cacher: %v\n", err)
+		}
+		return c
+	})
+}
+
+func TestFsCacherGetMulti(t *testing.T) {
+
+	c, err := NewCacher(t.TempDir())
+	if err != nil {
+		t.Fatalf("problem creating fs cacher: %v\n", err)
+	}
+
+	present := mlcache.NewCacheKey("present")
+	missing := mlcache.NewCacheKey("missing")
+	val := bytes.NewBuffer([]byte("hello"))
+
+	if _, err := c.Put(present, val, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("problem putting key: %v\n", err)
+	}
+
+	vals, _, err := c.GetMulti([]*mlcache.CacheKey{present, missing})
+	if err != nil {
+		t.Fatalf("GetMulti failed: %v\n", err)
+	}
+	if vals[0] == nil || vals[0].String() != "hello" {
+		t.Errorf("expected present key to resolve to \"hello\", got %v\n", vals[0])
+	}
+	if vals[1] != nil {
+		t.Errorf("expected missing key to resolve to nil, got %v\n", vals[1])
+	}
+
+	present.Done()
+	missing.Done()
+}
@@ -0,0 +1,182 @@
+// Package redis provides a Redis-backed mlcache.Cacher using go-redis,
+// suitable as a shared L2/L3 remote cache level.
+package redis
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/errs"
+)
+
+var _ mlcache.Cacher = &Cacher{}
+var _ mlcache.BatchCacher = &Cacher{}
+
+// Cacher wraps a go-redis client as a mlcache.Cacher. TTL is enforced with
+// EXPIREAT so expiry travels with the absolute time callers already pass
+// into Put.
+type Cacher struct {
+	client *goredis.Client
+	ctx    context.Context
+}
+
+// NewCacher wraps an existing go-redis client as a mlcache.Cacher.
+func NewCacher(client *goredis.Client) *Cacher {
+	return &Cacher{client: client, ctx: context.Background()}
+}
+
+// expiresAt converts the value returned by EXPIRETIME/Client.ExpireTime (the
+// key's absolute Unix expiry in seconds, carried as a time.Duration) into
+// the absolute expiry time mlcache.Cacher implementations report.
+func expiresAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(ttl/time.Second), 0).UTC()
+}
+
+// Get returns the cache item, if present
+func (c *Cacher) Get(key *mlcache.CacheKey) (*bytes.Buffer, time.Time, error) {
+
+	val, err := c.client.Get(c.ctx, key.AsString()).Bytes()
+	if err != nil {
+		return nil, time.Now().UTC(), errs.Build(err, mlcache.GetError)
+	}
+
+	ttl, err := c.client.ExpireTime(c.ctx, key.AsString()).Result()
+	if err != nil {
+		return nil, time.Now().UTC(), errs.Build(err, mlcache.GetError)
+	}
+
+	return bytes.NewBuffer(val), expiresAt(ttl), nil
+}
+
+// Put adds/updates a cache item
+func (c *Cacher) Put(key *mlcache.CacheKey, val *bytes.Buffer, expires time.Time) (mlcache.CacheStatus, error) {
+
+	data := []byte{}
+	if val != nil {
+		data = val.Bytes()
+	}
+
+	if err := c.client.Set(c.ctx, key.AsString(), data, 0).Err(); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+	}
+
+	if !expires.IsZero() {
+		if err := c.client.ExpireAt(c.ctx, key.AsString(), expires).Err(); err != nil {
+			return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+		}
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// Del deletes the key from the cache
+func (c *Cacher) Del(key *mlcache.CacheKey) (mlcache.CacheStatus, error) {
+
+	if err := c.client.Del(c.ctx, key.AsString()).Err(); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.DelError)
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// IsPresent returns true if the key is present
+func (c *Cacher) IsPresent(key *mlcache.CacheKey) (mlcache.CacheStatus, error) {
+
+	n, err := c.client.Exists(c.ctx, key.AsString()).Result()
+	if err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.IsPresentError)
+	}
+	if n == 0 {
+		return mlcache.CacheStatusFailure, nil
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// Flush clears all keys from the cache
+func (c *Cacher) Flush() error {
+
+	if err := c.client.FlushDB(c.ctx).Err(); err != nil {
+		return errs.Build(err, mlcache.FlushError)
+	}
+
+	return nil
+}
+
+// GetMulti implements mlcache.BatchGetter, fetching every value with a
+// single MGET round trip instead of issuing one GET per key. The expiry of
+// each key MGET found is then read with its own EXPIRETIME call.
+func (c *Cacher) GetMulti(keys []*mlcache.CacheKey) ([]*bytes.Buffer, []time.Time, error) {
+
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.AsString()
+	}
+
+	raws, err := c.client.MGet(c.ctx, names...).Result()
+	if err != nil {
+		return nil, nil, errs.Build(err, mlcache.GetError)
+	}
+
+	vals := make([]*bytes.Buffer, len(raws))
+	ttls := make([]time.Time, len(raws))
+	for i, raw := range raws {
+		if raw == nil {
+			continue
+		}
+		if s, ok := raw.(string); ok {
+			vals[i] = bytes.NewBufferString(s)
+		}
+		if ttl, err := c.client.ExpireTime(c.ctx, names[i]).Result(); err == nil {
+			ttls[i] = expiresAt(ttl)
+		}
+	}
+
+	return vals, ttls, nil
+}
+
+// PutMulti implements mlcache.BatchCacher using a single pipelined round
+// trip instead of one SET/EXPIREAT pair per key.
+func (c *Cacher) PutMulti(keys []*mlcache.CacheKey, vals []*bytes.Buffer, expires []time.Time) (mlcache.CacheStatus, error) {
+
+	pipe := c.client.Pipeline()
+	for i, key := range keys {
+		data := []byte{}
+		if vals[i] != nil {
+			data = vals[i].Bytes()
+		}
+		pipe.Set(c.ctx, key.AsString(), data, 0)
+		if !expires[i].IsZero() {
+			pipe.ExpireAt(c.ctx, key.AsString(), expires[i])
+		}
+	}
+
+	if _, err := pipe.Exec(c.ctx); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// DelMulti implements mlcache.BatchCacher using a single DEL round trip
+// instead of one per key.
+func (c *Cacher) DelMulti(keys []*mlcache.CacheKey) (mlcache.CacheStatus, error) {
+
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.AsString()
+	}
+
+	if err := c.client.Del(c.ctx, names...).Err(); err != nil {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.DelError)
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
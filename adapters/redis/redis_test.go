@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/cachertest"
+)
+
+func TestRedisCacherContract(t *testing.T) {
+
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("problem starting miniredis: %v\n", err)
+	}
+	defer server.Close()
+
+	cachertest.RunContractTests(t, func() mlcache.Cacher {
+		server.FlushAll()
+		client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+		return NewCacher(client)
+	})
+}
+
+func TestRedisCacherBatch(t *testing.T) {
+
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("problem starting miniredis: %v\n", err)
+	}
+	defer server.Close()
+
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	c := NewCacher(client)
+
+	k1, k2 := mlcache.NewCacheKey("batch1"), mlcache.NewCacheKey("batch2")
+	v1, v2 := bytes.NewBuffer([]byte("one")), bytes.NewBuffer([]byte("two"))
+	expires := time.Now().Add(time.Minute)
+
+	if status, err := c.PutMulti([]*mlcache.CacheKey{k1, k2}, []*bytes.Buffer{v1, v2}, []time.Time{expires, expires}); err != nil || status != mlcache.CacheStatusSuccess {
+		t.Fatalf("PutMulti failed: %v\n", err)
+	}
+
+	vals, _, err := c.GetMulti([]*mlcache.CacheKey{k1, k2})
+	if err != nil {
+		t.Fatalf("GetMulti failed: %v\n", err)
+	}
+	if vals[0].String() != "one" || vals[1].String() != "two" {
+		t.Errorf("GetMulti returned unexpected values: %q, %q\n", vals[0], vals[1])
+	}
+
+	if status, err := c.DelMulti([]*mlcache.CacheKey{k1, k2}); err != nil || status != mlcache.CacheStatusSuccess {
+		t.Fatalf("DelMulti failed: %v\n", err)
+	}
+	if status, _ := c.IsPresent(k1); status != mlcache.CacheStatusFailure {
+		t.Errorf("expected k1 to be gone after DelMulti\n")
+	}
+
+	k1.Done()
+	k2.Done()
+}
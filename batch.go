@@ -0,0 +1,281 @@
+package mlcache
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/gptankit/mlcache/errs"
+)
+
+var _ BatchCacher = &cacher{}
+
+// Bound on fan-out goroutines used to service a cache level that doesn't
+// implement BatchCacher/BatchGetter itself
+const defaultBatchFanOut = 16
+
+// validateKeys rejects a batch containing a nil key, mirroring the guard
+// Get/Put/Del each apply to their single key
+func validateKeys(keys []*CacheKey, onNil errs.ErrorMessage) error {
+	for _, key := range keys {
+		if key == nil {
+			return errs.New(onNil)
+		}
+	}
+	return nil
+}
+
+// fanOutGet services keys against cache with a bounded worker pool
+func fanOutGet(cache Cacher, keys []*CacheKey) ([]*bytes.Buffer, []time.Time, error) {
+
+	vals := make([]*bytes.Buffer, len(keys))
+	ttls := make([]time.Time, len(keys))
+
+	sem := make(chan struct{}, defaultBatchFanOut)
+	var wg sync.WaitGroup
+
+	for i, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, key *CacheKey) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			vals[i], ttls[i], _ = cache.Get(key)
+		}(i, key)
+	}
+	wg.Wait()
+
+	return vals, ttls, nil
+}
+
+// levelGet services keys against a single cache level in as few round
+// trips as possible, preferring BatchGetter over a bounded fan-out
+func levelGet(cache Cacher, keys []*CacheKey) ([]*bytes.Buffer, []time.Time, error) {
+
+	if bg, ok := cache.(BatchGetter); ok {
+		return bg.GetMulti(keys)
+	}
+
+	return fanOutGet(cache, keys)
+}
+
+func fanOutPut(cache Cacher, keys []*CacheKey, vals []*bytes.Buffer, expires []time.Time) (CacheStatus, error) {
+
+	status := CacheStatusSuccess
+	var mtx sync.Mutex
+
+	sem := make(chan struct{}, defaultBatchFanOut)
+	var wg sync.WaitGroup
+
+	for i, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, key *CacheKey) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if s, err := cache.Put(key, vals[i], expires[i]); err != nil || s == CacheStatusFailure {
+				mtx.Lock()
+				status = CacheStatusFailure
+				mtx.Unlock()
+			}
+		}(i, key)
+	}
+	wg.Wait()
+
+	return status, nil
+}
+
+func levelPut(cache Cacher, keys []*CacheKey, vals []*bytes.Buffer, expires []time.Time) (CacheStatus, error) {
+
+	if bc, ok := cache.(BatchCacher); ok {
+		return bc.PutMulti(keys, vals, expires)
+	}
+
+	return fanOutPut(cache, keys, vals, expires)
+}
+
+func fanOutDel(cache Cacher, keys []*CacheKey) (CacheStatus, error) {
+
+	status := CacheStatusSuccess
+	var mtx sync.Mutex
+
+	sem := make(chan struct{}, defaultBatchFanOut)
+	var wg sync.WaitGroup
+
+	for _, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key *CacheKey) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if s, err := cache.Del(key); err != nil || s == CacheStatusFailure {
+				mtx.Lock()
+				status = CacheStatusFailure
+				mtx.Unlock()
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	return status, nil
+}
+
+func levelDel(cache Cacher, keys []*CacheKey) (CacheStatus, error) {
+
+	if bc, ok := cache.(BatchCacher); ok {
+		return bc.DelMulti(keys)
+	}
+
+	return fanOutDel(cache, keys)
+}
+
+// GetMulti returns every key's cache item using the pre-selected read
+// pattern. Each level is serviced in a single round trip when it
+// implements BatchGetter, instead of walking the level once per key.
+func (ca *cacher) GetMulti(keys []*CacheKey) ([]*bytes.Buffer, []time.Time, error) {
+
+	if err := validateKeys(keys, GetError); err != nil {
+		return nil, nil, err
+	}
+
+	vals := make([]*bytes.Buffer, len(keys))
+	ttls := make([]time.Time, len(keys))
+
+	pending := make([]int, len(keys))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	this := ca.l1Cache
+	for this != nil && len(pending) > 0 {
+
+		pendingKeys := make([]*CacheKey, len(pending))
+		for i, idx := range pending {
+			pendingKeys[i] = keys[idx]
+		}
+
+		found, foundTTLs, err := levelGet(this.cur, pendingKeys)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stillPending := pending[:0]
+		for i, idx := range pending {
+			if found[i] == nil {
+				stillPending = append(stillPending, idx)
+				continue
+			}
+
+			vals[idx] = found[i]
+			ttls[idx] = foundTTLs[i]
+
+			lower := this.prev
+			if ca.readPattern == ReadThrough {
+				for lower != nil {
+					lower.cur.Put(keys[idx], found[i], foundTTLs[i])
+					lower = lower.prev
+				}
+			} else { // CacheAside
+				go func(lower *lCache, key *CacheKey, val *bytes.Buffer, ttl time.Time) {
+					for lower != nil {
+						lower.cur.Put(key, val, ttl)
+						lower = lower.prev
+					}
+				}(lower, keys[idx], found[i], foundTTLs[i])
+			}
+		}
+		pending = stillPending
+		this = this.next
+	}
+
+	return vals, ttls, nil
+}
+
+// PutMulti writes every key using the pre-selected write pattern. Each
+// level is serviced in a single round trip when it implements BatchCacher.
+func (ca *cacher) PutMulti(keys []*CacheKey, vals []*bytes.Buffer, expires []time.Time) (CacheStatus, error) {
+
+	if err := validateKeys(keys, PutError); err != nil {
+		return CacheStatusFailure, err
+	}
+	if len(vals) != len(keys) || len(expires) != len(keys) {
+		return CacheStatusFailure, errs.New(PutError)
+	}
+	for _, val := range vals {
+		if val != nil && val.Len() > ca.maxValSize {
+			return CacheStatusFailure, errs.New(MaxValLenExceeded)
+		}
+	}
+
+	switch ca.writePattern {
+	case WriteThrough:
+		this := ca.l1Cache
+		for this != nil {
+			status, err := levelPut(this.cur, keys, vals, expires)
+			if err != nil || status == CacheStatusFailure {
+				return CacheStatusFailure, errs.Build(err, PutError)
+			}
+			this = this.next
+		}
+		for i, key := range keys {
+			ca.publish(EventPut, key.AsString(), vals[i], expires[i])
+		}
+		return CacheStatusSuccess, nil
+
+	case WriteAround:
+		status, err := levelPut(ca.lnCache.cur, keys, vals, expires)
+		if err != nil || status == CacheStatusFailure {
+			return CacheStatusFailure, errs.Build(err, PutError)
+		}
+		for i, key := range keys {
+			ca.publish(EventPut, key.AsString(), vals[i], expires[i])
+		}
+		return CacheStatusSuccess, nil
+
+	case WriteBack:
+		this := ca.l1Cache
+		status, err := levelPut(this.cur, keys, vals, expires)
+		if err != nil || status == CacheStatusFailure {
+			return CacheStatusFailure, errs.Build(err, PutError)
+		}
+		for i, key := range keys {
+			ca.publish(EventPut, key.AsString(), vals[i], expires[i])
+
+			journalID := ""
+			if ca.writeBackJournal != nil {
+				if id, jerr := ca.writeBackJournal.Append(key.AsString(), vals[i], expires[i]); jerr == nil {
+					journalID = id
+				}
+			}
+
+			ca.writeBackCh <- writeBackJob{this: this, key: key, val: vals[i], ttl: expires[i], journalID: journalID}
+		}
+		return CacheStatusSuccess, nil
+	}
+
+	return CacheStatusSuccess, nil
+}
+
+// DelMulti removes every key from all cache levels, servicing each level
+// in a single round trip when it implements BatchCacher.
+func (ca *cacher) DelMulti(keys []*CacheKey) (CacheStatus, error) {
+
+	if err := validateKeys(keys, DelError); err != nil {
+		return CacheStatusFailure, err
+	}
+
+	this := ca.lnCache
+	for this != nil {
+		status, err := levelDel(this.cur, keys)
+		if err != nil || status == CacheStatusFailure {
+			return CacheStatusFailure, errs.Build(err, DelError)
+		}
+		this = this.prev
+	}
+
+	for _, key := range keys {
+		ca.publish(EventDel, key.AsString(), nil, time.Time{})
+	}
+
+	return CacheStatusSuccess, nil
+}
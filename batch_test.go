@@ -0,0 +1,119 @@
+package mlcache
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// batchCountingCacher wraps a mockCacher and implements BatchGetter,
+// counting how many times GetMulti is invoked, so tests can verify that
+// GetMulti is serviced in one round trip per level instead of per key.
+type batchCountingCacher struct {
+	*mockCacher
+	getMultiCalls int64
+}
+
+func newBatchCountingCacher() *batchCountingCacher {
+	return &batchCountingCacher{mockCacher: NewMockCacher().(*mockCacher)}
+}
+
+func (c *batchCountingCacher) GetMulti(keys []*CacheKey) ([]*bytes.Buffer, []time.Time, error) {
+
+	atomic.AddInt64(&c.getMultiCalls, 1)
+
+	vals := make([]*bytes.Buffer, len(keys))
+	ttls := make([]time.Time, len(keys))
+	for i, key := range keys {
+		vals[i], ttls[i], _ = c.mockCacher.Get(key)
+	}
+
+	return vals, ttls, nil
+}
+
+func TestGetMultiPrefersBatchGetter(t *testing.T) {
+
+	l1 := newBatchCountingCacher()
+	ml, err := NewMultiLevelCache(ReadThrough, WriteThrough, 100, []Cacher{l1})
+	if err != nil {
+		t.Fatalf("problem creating cacher: %v\n", err)
+	}
+
+	k1, k2 := NewCacheKey("k1"), NewCacheKey("k2")
+	l1.Put(k1, bytes.NewBuffer([]byte("v1")), time.Now().Add(time.Minute))
+	l1.Put(k2, bytes.NewBuffer([]byte("v2")), time.Now().Add(time.Minute))
+
+	vals, _, err := ml.(BatchCacher).GetMulti([]*CacheKey{k1, k2})
+	if err != nil {
+		t.Fatalf("GetMulti failed: %v\n", err)
+	}
+	if vals[0].String() != "v1" || vals[1].String() != "v2" {
+		t.Errorf("GetMulti returned unexpected values: %q, %q\n", vals[0], vals[1])
+	}
+
+	if got := atomic.LoadInt64(&l1.getMultiCalls); got != 1 {
+		t.Errorf("expected exactly 1 underlying GetMulti call, got %d\n", got)
+	}
+
+	k1.Done()
+	k2.Done()
+}
+
+func TestPutMultiAndDelMulti(t *testing.T) {
+
+	l1 := NewMockCacher()
+	ml, err := NewMultiLevelCache(ReadThrough, WriteThrough, 100, []Cacher{l1})
+	if err != nil {
+		t.Fatalf("problem creating cacher: %v\n", err)
+	}
+	bc := ml.(BatchCacher)
+
+	k1, k2 := NewCacheKey("b1"), NewCacheKey("b2")
+	v1, v2 := bytes.NewBuffer([]byte("one")), bytes.NewBuffer([]byte("two"))
+	expires := time.Now().Add(time.Minute)
+
+	if status, err := bc.PutMulti([]*CacheKey{k1, k2}, []*bytes.Buffer{v1, v2}, []time.Time{expires, expires}); err != nil || status != CacheStatusSuccess {
+		t.Fatalf("PutMulti failed: %v\n", err)
+	}
+
+	vals, _, err := bc.GetMulti([]*CacheKey{k1, k2})
+	if err != nil {
+		t.Fatalf("GetMulti failed: %v\n", err)
+	}
+	if vals[0].String() != "one" || vals[1].String() != "two" {
+		t.Errorf("GetMulti returned unexpected values: %q, %q\n", vals[0], vals[1])
+	}
+
+	if status, err := bc.DelMulti([]*CacheKey{k1, k2}); err != nil || status != CacheStatusSuccess {
+		t.Fatalf("DelMulti failed: %v\n", err)
+	}
+	if status, _ := ml.IsPresent(k1); status != CacheStatusFailure {
+		t.Errorf("expected k1 to be gone after DelMulti\n")
+	}
+
+	k1.Done()
+	k2.Done()
+}
+
+func TestPutMultiRejectsLengthMismatch(t *testing.T) {
+
+	l1 := NewMockCacher()
+	ml, err := NewMultiLevelCache(ReadThrough, WriteThrough, 100, []Cacher{l1})
+	if err != nil {
+		t.Fatalf("problem creating cacher: %v\n", err)
+	}
+	bc := ml.(BatchCacher)
+
+	k1, k2 := NewCacheKey("m1"), NewCacheKey("m2")
+	v1 := bytes.NewBuffer([]byte("one"))
+	expires := time.Now().Add(time.Minute)
+
+	status, err := bc.PutMulti([]*CacheKey{k1, k2}, []*bytes.Buffer{v1}, []time.Time{expires})
+	if err == nil || status != CacheStatusFailure {
+		t.Errorf("expected a length-mismatched PutMulti to fail instead of indexing out of range, got status=%v err=%v\n", status, err)
+	}
+
+	k1.Done()
+	k2.Done()
+}
@@ -27,6 +27,7 @@ const (
 	DelError              errs.ErrorMessage = "Del error"
 	IsPresentError        errs.ErrorMessage = "IsPresent error"
 	FlushError            errs.ErrorMessage = "Flush error"
+	InvalidByteSize       errs.ErrorMessage = "Invalid byte size"
 )
 
 type ReadPattern uint8
@@ -92,11 +93,22 @@ type cacher struct {
 	writePattern WritePattern
 	// Cache value size cutoff
 	maxValSize int
+	// Optional bus used to broadcast/receive cross-instance invalidations
+	eventBus EventBus
+	// Random per-instance id, used to ignore this cacher's own published events
+	instanceID string
+	// Coalesces concurrent Gets for the same key into a single backfill
+	inflight *inflightGroup
+	// WriteBack propagation settings; see startWriteBack
+	writeBackDelay   time.Duration
+	writeBackWorkers int
+	writeBackJournal WriteBackJournal
+	writeBackCh      chan writeBackJob
 }
 
 // NewMultiLevelCache creates a new mlcache object.
 // It expects 0 < num(caches) <= maxCaches and pre-defined read/write patterns to be passed in.
-func NewMultiLevelCache(readPattern ReadPattern, writePattern WritePattern, maxValSize int, caches ...Cacher) (Cacher, error) {
+func NewMultiLevelCache(readPattern ReadPattern, writePattern WritePattern, maxValSize int, caches []Cacher, opts ...Option) (Cacher, error) {
 
 	numCaches := uint8(len(caches))
 
@@ -121,8 +133,25 @@ func NewMultiLevelCache(readPattern ReadPattern, writePattern WritePattern, maxV
 		readPattern:  readPattern,
 		writePattern: writePattern,
 		maxValSize:   maxValSize,
+		instanceID:   newInstanceID(),
+		inflight:     newInflightGroup(),
 	}
 
+	for _, opt := range opts {
+		opt(ca)
+	}
+
+	if ca.eventBus != nil {
+		// Subscribe synchronously so this instance is registered with the
+		// bus before NewMultiLevelCache returns, otherwise a Publish from
+		// another instance immediately after construction can race the
+		// subscriber goroutine's startup and be silently dropped.
+		ch := ca.eventBus.Subscribe()
+		go ca.subscribe(ch)
+	}
+
+	ca.startWriteBack()
+
 	return ca, nil
 }
 
@@ -133,6 +162,18 @@ func (ca *cacher) Get(key *CacheKey) (*bytes.Buffer, time.Time, error) {
 		return nil, time.Now().UTC(), errs.New(GetError)
 	}
 
+	// Coalesce concurrent Gets for the same key behind a single
+	// lookup-and-backfill so a miss doesn't trigger a thundering herd of
+	// identical walks and Put chains across levels.
+	return ca.inflight.do(key.AsString(), func() (*bytes.Buffer, time.Time, error) {
+		return ca.getUncoalesced(key)
+	})
+}
+
+// getUncoalesced performs the actual cache walk for Get using the
+// pre-selected read pattern. Callers go through Get's inflight group.
+func (ca *cacher) getUncoalesced(key *CacheKey) (*bytes.Buffer, time.Time, error) {
+
 	switch ca.readPattern {
 	case ReadThrough:
 		this := ca.l1Cache
@@ -200,6 +241,7 @@ func (ca *cacher) Put(key *CacheKey, val *bytes.Buffer, ttl time.Time) (CacheSta
 			}
 			this = this.next
 		}
+		ca.publish(EventPut, key.AsString(), val, ttl)
 		return CacheStatusSuccess, nil
 
 	case WriteAround:
@@ -209,6 +251,7 @@ func (ca *cacher) Put(key *CacheKey, val *bytes.Buffer, ttl time.Time) (CacheSta
 		if err != nil || cacheStatus == CacheStatusFailure {
 			return CacheStatusFailure, errs.Build(err, PutError)
 		}
+		ca.publish(EventPut, key.AsString(), val, ttl)
 		return CacheStatusSuccess, nil
 
 	case WriteBack:
@@ -218,18 +261,19 @@ func (ca *cacher) Put(key *CacheKey, val *bytes.Buffer, ttl time.Time) (CacheSta
 		if err != nil || cacheStatus == CacheStatusFailure {
 			return CacheStatusFailure, errs.Build(err, PutError)
 		} else {
-			go func(writeBackDelay time.Duration) { // put in upper level caches with delay
-				time.Sleep(writeBackDelay)
+			ca.publish(EventPut, key.AsString(), val, ttl)
 
-				upper := this.next
-				for upper != nil {
-					status, err := upper.cur.Put(key, val, ttl)
-					if err != nil || status == CacheStatusFailure {
-						break
-					}
-					upper = upper.next
+			journalID := ""
+			if ca.writeBackJournal != nil {
+				if id, jerr := ca.writeBackJournal.Append(key.AsString(), val, ttl); jerr == nil {
+					journalID = id
 				}
-			}(200 * time.Millisecond) // write to higher level caches after waiting for this duration
+			}
+
+			// hand off to the bounded worker pool instead of spawning a
+			// goroutine per write, so that a burst of Puts queues rather
+			// than fanning out unbounded goroutines
+			ca.writeBackCh <- writeBackJob{this: this, key: key, val: val, ttl: ttl, journalID: journalID}
 		}
 		return CacheStatusSuccess, nil
 	}
@@ -255,6 +299,7 @@ func (ca *cacher) Del(key *CacheKey) (CacheStatus, error) {
 		this = this.prev
 	}
 
+	ca.publish(EventDel, key.AsString(), nil, time.Time{})
 	return CacheStatusSuccess, nil
 }
 
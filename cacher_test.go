@@ -101,7 +101,7 @@ func TestCacher(t *testing.T) {
 
 		// multi-level cacher with 3 levels of cache
 		l1Cache, l2Cache, l3Cache := NewMockCacher(), NewMockCacher(), NewMockCacher()
-		cacher, err := NewMultiLevelCache(test.readPattern, test.writePattern, test.maxValSize, l1Cache, l2Cache, l3Cache)
+		cacher, err := NewMultiLevelCache(test.readPattern, test.writePattern, test.maxValSize, []Cacher{l1Cache, l2Cache, l3Cache})
 		if err != nil {
 			t.Errorf("testid:%d: problem creating cacher\n", test.id)
 			t.FailNow()
@@ -155,7 +155,7 @@ func BenchmarkCacher(b *testing.B) {
 
 		// multi-level cacher with 3 levels of cache
 		l1Cache, l2Cache, l3Cache := NewMockCacher(), NewMockCacher(), NewMockCacher()
-		cacher, err := NewMultiLevelCache(test.readPattern, test.writePattern, test.maxValSize, l1Cache, l2Cache, l3Cache)
+		cacher, err := NewMultiLevelCache(test.readPattern, test.writePattern, test.maxValSize, []Cacher{l1Cache, l2Cache, l3Cache})
 		if err != nil {
 			b.Errorf("testid:%d: problem creating cacher\n", test.id)
 			b.FailNow()
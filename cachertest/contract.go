@@ -0,0 +1,85 @@
+// Package cachertest provides a reusable conformance suite that any
+// mlcache.Cacher implementation can be run against, so built-in adapters
+// are held to the same contract the in-package mockCacher satisfies.
+package cachertest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gptankit/mlcache"
+)
+
+// RunContractTests exercises Get/Put/Del/IsPresent/Flush against a fresh
+// Cacher returned by newCacher for each sub-test.
+func RunContractTests(t *testing.T, newCacher func() mlcache.Cacher) {
+
+	t.Run("PutThenGet", func(t *testing.T) {
+		c := newCacher()
+		key := mlcache.NewCacheKey("contract-putget")
+		val := bytes.NewBuffer([]byte("hello"))
+
+		if status, err := c.Put(key, val, time.Now().Add(time.Minute)); err != nil || status != mlcache.CacheStatusSuccess {
+			t.Fatalf("Put failed: %v\n", err)
+		}
+
+		got, _, err := c.Get(key)
+		if err != nil {
+			t.Fatalf("Get failed: %v\n", err)
+		}
+		if got.String() != "hello" {
+			t.Errorf("got %q, want %q\n", got.String(), "hello")
+		}
+
+		key.Done()
+	})
+
+	t.Run("IsPresent", func(t *testing.T) {
+		c := newCacher()
+		present := mlcache.NewCacheKey("contract-present")
+		absent := mlcache.NewCacheKey("contract-absent")
+
+		c.Put(present, bytes.NewBuffer([]byte("v")), time.Now().Add(time.Minute))
+
+		if status, _ := c.IsPresent(present); status != mlcache.CacheStatusSuccess {
+			t.Errorf("expected present key to report present\n")
+		}
+		if status, _ := c.IsPresent(absent); status != mlcache.CacheStatusFailure {
+			t.Errorf("expected absent key to report absent\n")
+		}
+
+		present.Done()
+		absent.Done()
+	})
+
+	t.Run("Del", func(t *testing.T) {
+		c := newCacher()
+		key := mlcache.NewCacheKey("contract-del")
+		c.Put(key, bytes.NewBuffer([]byte("v")), time.Now().Add(time.Minute))
+
+		if status, err := c.Del(key); err != nil || status != mlcache.CacheStatusSuccess {
+			t.Fatalf("Del failed: %v\n", err)
+		}
+		if status, _ := c.IsPresent(key); status != mlcache.CacheStatusFailure {
+			t.Errorf("expected deleted key to report absent\n")
+		}
+
+		key.Done()
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		c := newCacher()
+		key := mlcache.NewCacheKey("contract-flush")
+		c.Put(key, bytes.NewBuffer([]byte("v")), time.Now().Add(time.Minute))
+
+		if err := c.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v\n", err)
+		}
+		if status, _ := c.IsPresent(key); status != mlcache.CacheStatusFailure {
+			t.Errorf("expected key to be gone after Flush\n")
+		}
+
+		key.Done()
+	})
+}
@@ -20,6 +20,9 @@ func Build(err error, userMessage ErrorMessage) error {
 	if userMessage == "" {
 		return err
 	}
+	if err == nil {
+		return New(userMessage)
+	}
 
 	return errors.New(err.Error() + "; " + string(userMessage))
 }
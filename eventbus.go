@@ -0,0 +1,195 @@
+package mlcache
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of cache mutation an Event carries
+type EventType uint8
+
+const (
+	// EventPut signals that a fresh value was written for a key
+	EventPut EventType = iota
+	// EventDel signals that a key was removed
+	EventDel
+)
+
+// Event is a single cache mutation broadcast over an EventBus so that other
+// mlcache instances sharing an upper-level cache can keep their local
+// levels coherent.
+type Event struct {
+	Type     EventType
+	Key      string
+	Val      *bytes.Buffer
+	Expires  time.Time
+	SourceID string // per-instance id of the publisher, used for dedup
+}
+
+// EventBus lets multiple mlcache instances invalidate each other's local
+// cache levels when they share an upper-level cache (e.g. a Redis L2).
+type EventBus interface {
+	// Publish broadcasts an event to every subscriber
+	Publish(event Event) error
+	// Subscribe returns a channel of events published on the bus, including
+	// the subscribing instance's own; subscribers skip those by SourceID.
+	// The channel is closed once the bus is closed.
+	Subscribe() <-chan Event
+	// Close releases the bus's resources. Safe to call more than once.
+	Close() error
+}
+
+// Option configures a cacher at construction time
+type Option func(*cacher)
+
+// WithEventBus wires an EventBus into the cacher so that Put/Del mutations
+// are broadcast, and invalidations from other instances are applied to
+// this instance's local cache levels.
+func WithEventBus(bus EventBus) Option {
+	return func(ca *cacher) {
+		ca.eventBus = bus
+	}
+}
+
+// newInstanceID returns a random per-process id used to recognise (and
+// skip) an instance's own published events
+func newInstanceID() string {
+
+	buf := make([]byte, 16)
+	rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
+
+// publish broadcasts a mutation event, if an EventBus is configured
+func (ca *cacher) publish(evType EventType, key string, val *bytes.Buffer, expires time.Time) {
+
+	if ca.eventBus == nil {
+		return
+	}
+
+	ca.eventBus.Publish(Event{
+		Type:     evType,
+		Key:      key,
+		Val:      val,
+		Expires:  expires,
+		SourceID: ca.instanceID,
+	})
+}
+
+// subscribe applies events published by other instances to every local
+// cache level, keeping them coherent with a shared upper-level cache. ch
+// must come from a Subscribe() call made synchronously at construction
+// time, before any other instance's Publish can race this one's startup.
+func (ca *cacher) subscribe(ch <-chan Event) {
+
+	for ev := range ch {
+
+		if ev.SourceID == ca.instanceID { // dedup own publications
+			continue
+		}
+
+		key := NewCacheKey(ev.Key)
+
+		this := ca.l1Cache
+		for this != nil {
+			switch ev.Type {
+			case EventDel:
+				this.cur.Del(key)
+			case EventPut:
+				this.cur.Put(key, ev.Val, ev.Expires)
+			}
+			this = this.next
+		}
+
+		key.Done()
+	}
+}
+
+// Close stops the write-back worker pool and releases the journal and
+// event bus this cacher was constructed with, if any. It is safe to call
+// even when none of them were configured.
+func (ca *cacher) Close() error {
+
+	if ca.writeBackCh != nil {
+		close(ca.writeBackCh)
+	}
+	if ca.writeBackJournal != nil {
+		ca.writeBackJournal.Close()
+	}
+
+	if ca.eventBus == nil {
+		return nil
+	}
+
+	return ca.eventBus.Close()
+}
+
+// channelEventBus is an in-process EventBus backed by a fan-out of Go
+// channels. It is useful for tests and single-process deployments that
+// still want multiple *cacher instances to stay coherent.
+type channelEventBus struct {
+	mtx    sync.Mutex
+	subs   []chan Event
+	closed bool
+}
+
+// NewChannelEventBus creates an in-process EventBus.
+func NewChannelEventBus() EventBus {
+	return &channelEventBus{}
+}
+
+func (b *channelEventBus) Publish(event Event) error {
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	for _, sub := range b.subs {
+		select {
+		case sub <- event:
+		default: // drop if a subscriber isn't keeping up, rather than block publishers
+		}
+	}
+
+	return nil
+}
+
+func (b *channelEventBus) Subscribe() <-chan Event {
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	sub := make(chan Event, 64)
+	if b.closed {
+		close(sub)
+		return sub
+	}
+
+	b.subs = append(b.subs, sub)
+
+	return sub
+}
+
+func (b *channelEventBus) Close() error {
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	for _, sub := range b.subs {
+		close(sub)
+	}
+
+	return nil
+}
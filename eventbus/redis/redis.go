@@ -0,0 +1,124 @@
+// Package redis provides a Redis Pub/Sub backed mlcache.EventBus, so that
+// independent processes sharing a Redis-backed cache level can invalidate
+// each other's local cache levels.
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/gptankit/mlcache"
+)
+
+// Default Pub/Sub channel used when none is supplied to NewEventBus
+const defaultChannel = "mlcache:invalidate"
+
+var _ mlcache.EventBus = &EventBus{}
+
+// wireEvent is the JSON wire form of mlcache.Event; *bytes.Buffer isn't
+// directly marshalable, so Val travels as raw bytes.
+type wireEvent struct {
+	Type     mlcache.EventType `json:"type"`
+	Key      string            `json:"key"`
+	Val      []byte            `json:"val,omitempty"`
+	Expires  time.Time         `json:"expires"`
+	SourceID string            `json:"source_id"`
+}
+
+// EventBus is a Redis Pub/Sub backed mlcache.EventBus.
+type EventBus struct {
+	client  *goredis.Client
+	channel string
+	pubsub  *goredis.PubSub
+	ctx     context.Context
+	cancel  context.CancelFunc
+	out     chan mlcache.Event
+}
+
+// NewEventBus creates an EventBus that publishes/subscribes on the given
+// Redis Pub/Sub channel. If channel is empty, a package default is used.
+func NewEventBus(client *goredis.Client, channel string) *EventBus {
+
+	if channel == "" {
+		channel = defaultChannel
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	bus := &EventBus{
+		client:  client,
+		channel: channel,
+		pubsub:  client.Subscribe(ctx, channel),
+		ctx:     ctx,
+		cancel:  cancel,
+		out:     make(chan mlcache.Event, 64),
+	}
+
+	go bus.relay()
+
+	return bus
+}
+
+// relay decodes incoming Redis Pub/Sub messages onto the out channel until
+// the underlying subscription channel is closed
+func (b *EventBus) relay() {
+
+	defer close(b.out)
+
+	for msg := range b.pubsub.Channel() {
+
+		var wire wireEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &wire); err != nil {
+			continue // ignore malformed payloads rather than killing the relay
+		}
+
+		ev := mlcache.Event{
+			Type:     wire.Type,
+			Key:      wire.Key,
+			Expires:  wire.Expires,
+			SourceID: wire.SourceID,
+		}
+		if wire.Val != nil {
+			ev.Val = bytes.NewBuffer(wire.Val)
+		}
+
+		b.out <- ev
+	}
+}
+
+// Publish broadcasts event on the configured Redis channel
+func (b *EventBus) Publish(event mlcache.Event) error {
+
+	wire := wireEvent{
+		Type:     event.Type,
+		Key:      event.Key,
+		Expires:  event.Expires,
+		SourceID: event.SourceID,
+	}
+	if event.Val != nil {
+		wire.Val = event.Val.Bytes()
+	}
+
+	payload, err := json.Marshal(wire)
+	if err != nil {
+		return err
+	}
+
+	return b.client.Publish(b.ctx, b.channel, payload).Err()
+}
+
+// Subscribe returns the channel of events relayed from Redis. It is closed
+// once Close is called.
+func (b *EventBus) Subscribe() <-chan mlcache.Event {
+	return b.out
+}
+
+// Close unsubscribes from Redis and stops the relay goroutine
+func (b *EventBus) Close() error {
+	b.cancel()
+	return b.pubsub.Close()
+}
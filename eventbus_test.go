@@ -0,0 +1,38 @@
+package mlcache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestEventBusPropagatesAcrossInstances(t *testing.T) {
+
+	bus := NewChannelEventBus()
+
+	l1A, l1B := NewMockCacher(), NewMockCacher()
+
+	instA, err := NewMultiLevelCache(ReadThrough, WriteThrough, 100, []Cacher{l1A}, WithEventBus(bus))
+	if err != nil {
+		t.Fatalf("problem creating instA: %v\n", err)
+	}
+	instB, err := NewMultiLevelCache(ReadThrough, WriteThrough, 100, []Cacher{l1B}, WithEventBus(bus))
+	if err != nil {
+		t.Fatalf("problem creating instB: %v\n", err)
+	}
+	defer instA.(*cacher).Close()
+	defer instB.(*cacher).Close()
+
+	key := NewCacheKey("sharedkey")
+	val := bytes.NewBuffer([]byte("sharedval"))
+
+	instA.Put(key, val, time.Now().Add(5*time.Second))
+
+	time.Sleep(50 * time.Millisecond) // let instB's subscriber goroutine catch up
+
+	if status, _ := l1B.IsPresent(key); status != CacheStatusSuccess {
+		t.Errorf("expected instB's local cache to receive the invalidation event\n")
+	}
+
+	key.Done()
+}
@@ -0,0 +1,289 @@
+package mlcache
+
+import "container/list"
+
+// EvictionPolicy selects the bookkeeping strategy NewMemoryCache uses to
+// pick a victim once the cache is over its byte capacity.
+type EvictionPolicy uint8
+
+const (
+	// LRU evicts the least recently used entry
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used entry, tracked in O(1) via
+	// a doubly linked list of frequency buckets
+	LFU
+	// TinyLFU wraps LRU eviction order with a frequency-based admission
+	// filter so that a cold, rarely-seen key cannot evict a hotter one
+	TinyLFU
+	// End marker, no biggie
+	endPolicy
+)
+
+// evictor tracks per-key access bookkeeping for an eviction policy and
+// hands back the next key to reclaim when the cache is over capacity.
+type evictor interface {
+	// add registers a freshly inserted key
+	add(key string)
+	// touch records an access (cache hit) against key
+	touch(key string)
+	// remove unregisters a key on explicit delete or eviction
+	remove(key string)
+	// admit decides whether candidate may be inserted given the current
+	// victim that would have to be evicted to make room for it
+	admit(candidate string, victim string) bool
+	// victim returns the key that should be evicted next, if any
+	victim() (string, bool)
+}
+
+// newEvictor returns the bookkeeping structure for the given policy
+func newEvictor(policy EvictionPolicy) evictor {
+
+	switch policy {
+	case LFU:
+		return newLFUEvictor()
+	case TinyLFU:
+		return newTinyLFUEvictor()
+	default:
+		return newLRUEvictor()
+	}
+}
+
+// lruEvictor orders keys by recency using a doubly linked list
+type lruEvictor struct {
+	ll   *list.List
+	elem map[string]*list.Element
+}
+
+func newLRUEvictor() *lruEvictor {
+	return &lruEvictor{
+		ll:   list.New(),
+		elem: make(map[string]*list.Element),
+	}
+}
+
+func (e *lruEvictor) add(key string) {
+	e.elem[key] = e.ll.PushFront(key)
+}
+
+func (e *lruEvictor) touch(key string) {
+	if el, ok := e.elem[key]; ok {
+		e.ll.MoveToFront(el)
+	}
+}
+
+func (e *lruEvictor) remove(key string) {
+	if el, ok := e.elem[key]; ok {
+		e.ll.Remove(el)
+		delete(e.elem, key)
+	}
+}
+
+func (e *lruEvictor) admit(candidate string, victim string) bool {
+	return true
+}
+
+func (e *lruEvictor) victim() (string, bool) {
+	back := e.ll.Back()
+	if back == nil {
+		return "", false
+	}
+	return back.Value.(string), true
+}
+
+// freqNode is a bucket holding every key currently at a given access
+// frequency. keys preserves insertion order within the bucket (oldest at
+// the front) so that ties within a frequency break FIFO, not arbitrarily.
+type freqNode struct {
+	freq int
+	keys *list.List
+	prev *freqNode
+	next *freqNode
+}
+
+// lfuEntry locates a key within its current frequency bucket
+type lfuEntry struct {
+	bucket *freqNode
+	elem   *list.Element
+}
+
+// lfuEvictor is an O(1) LFU implementation: entries live in a node list
+// keyed by their access frequency; on access, an entry moves to the
+// next-higher frequency bucket (creating it if absent); on eviction, the
+// oldest entry in the lowest-frequency bucket is dropped.
+type lfuEvictor struct {
+	head  *freqNode // lowest frequency bucket
+	nodes map[string]*lfuEntry
+}
+
+func newLFUEvictor() *lfuEvictor {
+	return &lfuEvictor{
+		nodes: make(map[string]*lfuEntry),
+	}
+}
+
+// bucketFor returns the node for freq, inserting it right after the given
+// node (or at the head when after is nil) if absent
+func (e *lfuEvictor) bucketFor(freq int, after *freqNode) *freqNode {
+
+	if after != nil && after.next != nil && after.next.freq == freq {
+		return after.next
+	}
+	if after == nil && e.head != nil && e.head.freq == freq {
+		return e.head
+	}
+
+	node := &freqNode{freq: freq, keys: list.New()}
+	if after == nil {
+		node.next = e.head
+		if e.head != nil {
+			e.head.prev = node
+		}
+		e.head = node
+	} else {
+		node.prev = after
+		node.next = after.next
+		if after.next != nil {
+			after.next.prev = node
+		}
+		after.next = node
+	}
+
+	return node
+}
+
+// unlink removes an emptied bucket from the node list
+func (e *lfuEvictor) unlink(node *freqNode) {
+
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		e.head = node.next
+	}
+	if node.next != nil {
+		node.next.prev = node.prev
+	}
+}
+
+func (e *lfuEvictor) add(key string) {
+
+	bucket := e.bucketFor(1, nil)
+	elem := bucket.keys.PushBack(key)
+	e.nodes[key] = &lfuEntry{bucket: bucket, elem: elem}
+}
+
+func (e *lfuEvictor) touch(key string) {
+
+	entry, ok := e.nodes[key]
+	if !ok {
+		return
+	}
+
+	cur := entry.bucket
+	next := e.bucketFor(cur.freq+1, cur)
+
+	cur.keys.Remove(entry.elem)
+	entry.bucket = next
+	entry.elem = next.keys.PushBack(key)
+
+	if cur.keys.Len() == 0 {
+		e.unlink(cur)
+	}
+}
+
+func (e *lfuEvictor) remove(key string) {
+
+	entry, ok := e.nodes[key]
+	if !ok {
+		return
+	}
+
+	entry.bucket.keys.Remove(entry.elem)
+	delete(e.nodes, key)
+	if entry.bucket.keys.Len() == 0 {
+		e.unlink(entry.bucket)
+	}
+}
+
+func (e *lfuEvictor) admit(candidate string, victim string) bool {
+	return true
+}
+
+func (e *lfuEvictor) victim() (string, bool) {
+
+	if e.head == nil {
+		return "", false
+	}
+	front := e.head.keys.Front()
+	if front == nil {
+		return "", false
+	}
+	return front.Value.(string), true
+}
+
+// tinyLFUEvictor keeps LRU recency order for eviction but gates admission
+// of a brand new key on a lightweight frequency estimate, so a key seen
+// once cannot evict a key that is accessed often.
+type tinyLFUEvictor struct {
+	*lruEvictor
+	sketch         map[string]uint8
+	sampleSize     int
+	samplesCounted int
+}
+
+const tinyLFUResetSampleSize = 10000
+
+func newTinyLFUEvictor() *tinyLFUEvictor {
+	return &tinyLFUEvictor{
+		lruEvictor: newLRUEvictor(),
+		sketch:     make(map[string]uint8),
+		sampleSize: tinyLFUResetSampleSize,
+	}
+}
+
+func (e *tinyLFUEvictor) touch(key string) {
+	e.lruEvictor.touch(key)
+	e.record(key)
+}
+
+func (e *tinyLFUEvictor) add(key string) {
+	e.lruEvictor.add(key)
+	e.record(key)
+}
+
+// remove drops key's recency entry and its sketch count, so evicted/deleted
+// keys don't accumulate in the sketch forever
+func (e *tinyLFUEvictor) remove(key string) {
+	e.lruEvictor.remove(key)
+	delete(e.sketch, key)
+}
+
+// record increments the frequency estimate for key, halving every counter
+// once enough samples have been seen to keep the sketch bounded and fresh
+func (e *tinyLFUEvictor) record(key string) {
+
+	if e.sketch[key] < 255 {
+		e.sketch[key]++
+	}
+
+	e.samplesCounted++
+	if e.samplesCounted >= e.sampleSize {
+		for k, v := range e.sketch {
+			e.sketch[k] = v / 2
+		}
+		e.samplesCounted = 0
+	}
+}
+
+// admit rejects a candidate that is estimated to be colder than the
+// victim it would otherwise displace. The comparison runs against the
+// candidate's count as of the start of this attempt, but the attempt
+// itself is always recorded afterwards — otherwise a key rejected once
+// would never accumulate further samples and could never be admitted on
+// a later, more deserving attempt.
+func (e *tinyLFUEvictor) admit(candidate string, victim string) bool {
+
+	admitted := e.sketch[candidate] >= e.sketch[victim]
+	e.record(candidate)
+
+	return admitted
+}
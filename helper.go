@@ -1,6 +1,11 @@
 package mlcache
 
-import "github.com/gptankit/mlcache/errs"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gptankit/mlcache/errs"
+)
 
 // validate checks if the input parameters are within allowed limits
 func validate(numCaches uint8, readPattern ReadPattern, writePattern WritePattern) error {
@@ -23,3 +28,31 @@ func validate(numCaches uint8, readPattern ReadPattern, writePattern WritePatter
 
 	return nil
 }
+
+// byteSizeSuffixes maps a human-readable capacity suffix to its byte multiplier
+var byteSizeSuffixes = map[string]int64{
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+}
+
+// ParseByteSize converts a "64MB"-style string into a number of bytes.
+// Supported suffixes are B, KB, MB and GB (case-insensitive).
+func ParseByteSize(size string) (int64, error) {
+
+	size = strings.TrimSpace(strings.ToUpper(size))
+
+	for _, suffix := range []string{"GB", "MB", "KB", "B"} {
+		if strings.HasSuffix(size, suffix) {
+			numPart := strings.TrimSpace(strings.TrimSuffix(size, suffix))
+			num, err := strconv.ParseInt(numPart, 10, 64)
+			if err != nil {
+				return 0, errs.Build(err, InvalidByteSize)
+			}
+			return num * byteSizeSuffixes[suffix], nil
+		}
+	}
+
+	return 0, errs.New(InvalidByteSize)
+}
@@ -0,0 +1,81 @@
+package mlcache
+
+import (
+	"bytes"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// Number of shards backing the inflight map. Each shard has its own mutex
+// so that lookups for keys hashed to different shards never contend with
+// each other.
+const inflightShards = 32
+
+// inflightCall tracks a single in-progress Get for a key so that
+// concurrent callers can share its result instead of each walking every
+// cache level and issuing their own backfill chain.
+type inflightCall struct {
+	wg  sync.WaitGroup
+	val *bytes.Buffer
+	ttl time.Time
+	err error
+}
+
+type inflightShard struct {
+	mtx   sync.Mutex
+	calls map[string]*inflightCall
+}
+
+// inflightGroup coalesces concurrent Gets for the same key behind a single
+// underlying lookup-and-backfill, with followers receiving the leader's
+// result.
+type inflightGroup struct {
+	shards [inflightShards]*inflightShard
+}
+
+func newInflightGroup() *inflightGroup {
+
+	g := &inflightGroup{}
+	for i := range g.shards {
+		g.shards[i] = &inflightShard{calls: make(map[string]*inflightCall)}
+	}
+
+	return g
+}
+
+func (g *inflightGroup) shardFor(key string) *inflightShard {
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+
+	return g.shards[h.Sum32()%inflightShards]
+}
+
+// do runs fn for key if no call is already in flight for it; otherwise it
+// waits for the in-flight call to finish and returns its result.
+func (g *inflightGroup) do(key string, fn func() (*bytes.Buffer, time.Time, error)) (*bytes.Buffer, time.Time, error) {
+
+	shard := g.shardFor(key)
+
+	shard.mtx.Lock()
+	if call, ok := shard.calls[key]; ok {
+		shard.mtx.Unlock()
+		call.wg.Wait()
+		return call.val, call.ttl, call.err
+	}
+
+	call := &inflightCall{}
+	call.wg.Add(1)
+	shard.calls[key] = call
+	shard.mtx.Unlock()
+
+	call.val, call.ttl, call.err = fn()
+	call.wg.Done()
+
+	shard.mtx.Lock()
+	delete(shard.calls, key)
+	shard.mtx.Unlock()
+
+	return call.val, call.ttl, call.err
+}
@@ -0,0 +1,97 @@
+package mlcache
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingCacher wraps a mockCacher and counts Get calls, used to verify
+// that concurrent Gets for the same key are coalesced into one call. Get
+// blocks until release is closed, so whichever caller becomes the
+// in-flight leader stays in fn() for as long as the test needs — holding
+// the coalescing window open instead of racing a near-instant call.
+type countingCacher struct {
+	*mockCacher
+	gets    int64
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (c *countingCacher) Get(key *CacheKey) (*bytes.Buffer, time.Time, error) {
+	atomic.AddInt64(&c.gets, 1)
+	close(c.entered)
+	<-c.release
+	return c.mockCacher.Get(key)
+}
+
+func newCountingCacher() *countingCacher {
+	return &countingCacher{
+		mockCacher: NewMockCacher().(*mockCacher),
+		entered:    make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+}
+
+func TestGetCoalescesConcurrentCallers(t *testing.T) {
+
+	l1 := newCountingCacher()
+	cacher, err := NewMultiLevelCache(ReadThrough, WriteThrough, 100, []Cacher{l1})
+	if err != nil {
+		t.Fatalf("problem creating cacher: %v\n", err)
+	}
+
+	key := NewCacheKey("hot")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cacher.Get(key)
+		}()
+	}
+
+	<-l1.entered // wait for the leader to be in flight, blocked inside Get
+
+	// give the other 99 goroutines a real window to reach the "call
+	// already in flight, wait for it" branch before the leader proceeds;
+	// the leader stays blocked on release regardless of how long this
+	// takes, so there's no race against it finishing early
+	time.Sleep(100 * time.Millisecond)
+
+	close(l1.release)
+	wg.Wait()
+
+	if got := atomic.LoadInt64(&l1.gets); got != 1 {
+		t.Errorf("expected exactly 1 underlying Get for 100 concurrent callers, got %d\n", got)
+	}
+
+	key.Done()
+}
+
+// BenchmarkGetSameKeyConcurrent demonstrates the fan-in win: many
+// goroutines hitting the same key share one lookup instead of each
+// walking every cache level independently.
+func BenchmarkGetSameKeyConcurrent(b *testing.B) {
+
+	l1 := NewMockCacher()
+	cacher, err := NewMultiLevelCache(ReadThrough, WriteThrough, 100, []Cacher{l1})
+	if err != nil {
+		b.Fatalf("problem creating cacher: %v\n", err)
+	}
+
+	key := NewCacheKey("hot")
+	l1.Put(key, bytes.NewBuffer([]byte("v")), time.Now().Add(time.Minute))
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			cacher.Get(key)
+		}
+	})
+
+	key.Done()
+}
@@ -0,0 +1,292 @@
+package mlcache
+
+import (
+	"bytes"
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/gptankit/mlcache/errs"
+)
+
+var _ Cacher = &memoryCache{}
+
+// Default interval the background sweeper uses to purge expired entries
+const defaultSweepInterval = 1 * time.Second
+
+// memEntry is a single stored value along with its accounting metadata
+type memEntry struct {
+	val     *bytes.Buffer
+	expires time.Time
+	size    int64
+}
+
+// expiryHeap is a min-heap of entries ordered by expiry time, used to find
+// and purge expired keys without scanning the whole cache
+type expiryHeap []*expiryItem
+
+type expiryItem struct {
+	key     string
+	expires time.Time
+	index   int
+}
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	item := x.(*expiryItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
+
+// memoryCache is a built-in in-memory Cacher with a byte-size capacity and
+// a pluggable eviction policy (LRU, LFU or TinyLFU). Per-entry TTL is
+// enforced lazily on Get/Put and by a background sweeper.
+type memoryCache struct {
+	mtx sync.Mutex
+
+	capacityBytes int64
+	usedBytes     int64
+	policy        EvictionPolicy
+
+	items   map[string]*memEntry
+	expiry  expiryHeap
+	expItem map[string]*expiryItem
+	evictor evictor
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewMemoryCache creates a byte-capacity-bound in-memory Cacher using the
+// given eviction policy. It can be plugged into NewMultiLevelCache as any
+// combination of L1/L2/... levels, e.g. an LRU L1 backed by an LFU L2.
+func NewMemoryCache(capacityBytes int64, policy EvictionPolicy) Cacher {
+
+	mc := &memoryCache{
+		capacityBytes: capacityBytes,
+		policy:        policy,
+		items:         make(map[string]*memEntry),
+		expItem:       make(map[string]*expiryItem),
+		evictor:       newEvictor(policy),
+		stopCh:        make(chan struct{}),
+	}
+
+	heap.Init(&mc.expiry)
+	go mc.sweep(defaultSweepInterval)
+
+	return mc
+}
+
+// Get returns the cache item, if present and not expired
+func (mc *memoryCache) Get(key *CacheKey) (*bytes.Buffer, time.Time, error) {
+
+	if key == nil {
+		return nil, time.Now().UTC(), errs.New(GetError)
+	}
+
+	mc.mtx.Lock()
+	defer mc.mtx.Unlock()
+
+	entry, ok := mc.items[key.AsString()]
+	if !ok || mc.expired(entry) {
+		return nil, time.Now().UTC(), errs.New(GetError)
+	}
+
+	mc.evictor.touch(key.AsString())
+
+	return entry.val, entry.expires, nil
+}
+
+// Put adds/updates a cache item, evicting as many entries as needed to
+// keep total stored bytes within capacity
+func (mc *memoryCache) Put(key *CacheKey, val *bytes.Buffer, expires time.Time) (CacheStatus, error) {
+
+	if key == nil {
+		return CacheStatusFailure, errs.New(PutError)
+	}
+
+	mc.mtx.Lock()
+	defer mc.mtx.Unlock()
+
+	// purge already-expired entries first so they are reclaimed before
+	// any live entry is considered for eviction
+	now := time.Now().UTC()
+	for mc.expiry.Len() > 0 && !mc.expiry[0].expires.After(now) {
+		mc.removeLocked(mc.expiry[0].key)
+	}
+
+	k := key.AsString()
+	size := int64(0)
+	if val != nil {
+		size = int64(val.Len())
+	}
+
+	if _, exists := mc.items[k]; exists {
+		mc.removeLocked(k)
+	} else if victim, ok := mc.evictor.victim(); ok && mc.usedBytes+size > mc.capacityBytes {
+		if !mc.evictor.admit(k, victim) {
+			return CacheStatusSuccess, nil // candidate colder than the incumbent victim, reject admission
+		}
+	}
+
+	// make room before inserting, so the new key is never picked as the
+	// victim of its own Put
+	for mc.usedBytes+size > mc.capacityBytes {
+		victim, ok := mc.evictor.victim()
+		if !ok {
+			break
+		}
+		mc.removeLocked(victim)
+	}
+
+	mc.items[k] = &memEntry{val: val, expires: expires, size: size}
+	mc.usedBytes += size
+	mc.evictor.add(k)
+	mc.setExpiryLocked(k, expires)
+
+	return CacheStatusSuccess, nil
+}
+
+// Del deletes the key from the cache
+func (mc *memoryCache) Del(key *CacheKey) (CacheStatus, error) {
+
+	if key == nil {
+		return CacheStatusFailure, errs.New(DelError)
+	}
+
+	mc.mtx.Lock()
+	defer mc.mtx.Unlock()
+
+	mc.removeLocked(key.AsString())
+
+	return CacheStatusSuccess, nil
+}
+
+// IsPresent returns true if the key is present and not expired
+func (mc *memoryCache) IsPresent(key *CacheKey) (CacheStatus, error) {
+
+	if key == nil {
+		return CacheStatusFailure, errs.New(IsPresentError)
+	}
+
+	mc.mtx.Lock()
+	defer mc.mtx.Unlock()
+
+	entry, ok := mc.items[key.AsString()]
+	if !ok || mc.expired(entry) {
+		return CacheStatusFailure, nil
+	}
+
+	return CacheStatusSuccess, nil
+}
+
+// Flush clears all keys from the cache
+func (mc *memoryCache) Flush() error {
+
+	mc.mtx.Lock()
+	defer mc.mtx.Unlock()
+
+	mc.items = make(map[string]*memEntry)
+	mc.expItem = make(map[string]*expiryItem)
+	mc.expiry = mc.expiry[:0]
+	mc.usedBytes = 0
+	mc.evictor = newEvictor(mc.policy)
+
+	return nil
+}
+
+// Close stops the background TTL sweeper. It is safe to call more than once.
+func (mc *memoryCache) Close() {
+	mc.stopOnce.Do(func() {
+		close(mc.stopCh)
+	})
+}
+
+func (mc *memoryCache) expired(entry *memEntry) bool {
+	return !entry.expires.IsZero() && entry.expires.Before(time.Now().UTC())
+}
+
+// removeLocked evicts/deletes key from every bookkeeping structure. Caller
+// must hold mc.mtx.
+func (mc *memoryCache) removeLocked(key string) {
+
+	entry, ok := mc.items[key]
+	if !ok {
+		return
+	}
+
+	delete(mc.items, key)
+	mc.usedBytes -= entry.size
+	mc.evictor.remove(key)
+
+	if item, ok := mc.expItem[key]; ok {
+		heap.Remove(&mc.expiry, item.index)
+		delete(mc.expItem, key)
+	}
+}
+
+// setExpiryLocked (re)schedules key's entry in the expiry heap. Caller must
+// hold mc.mtx.
+func (mc *memoryCache) setExpiryLocked(key string, expires time.Time) {
+
+	if item, ok := mc.expItem[key]; ok {
+		heap.Remove(&mc.expiry, item.index)
+		delete(mc.expItem, key)
+	}
+
+	if expires.IsZero() {
+		return
+	}
+
+	item := &expiryItem{key: key, expires: expires}
+	heap.Push(&mc.expiry, item)
+	mc.expItem[key] = item
+}
+
+// sweep periodically purges expired entries in the background so that a
+// key that is never Get/Put again still has its bytes reclaimed
+func (mc *memoryCache) sweep(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mc.stopCh:
+			return
+		case <-ticker.C:
+			mc.sweepExpired()
+		}
+	}
+}
+
+func (mc *memoryCache) sweepExpired() {
+
+	mc.mtx.Lock()
+	defer mc.mtx.Unlock()
+
+	now := time.Now().UTC()
+	for mc.expiry.Len() > 0 {
+		next := mc.expiry[0]
+		if next.expires.After(now) {
+			break
+		}
+		mc.removeLocked(next.key)
+	}
+}
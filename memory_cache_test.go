@@ -0,0 +1,132 @@
+package mlcache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestParseByteSize(t *testing.T) {
+
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"64MB", 64 * 1 << 20, false},
+		{"1GB", 1 << 30, false},
+		{"512KB", 512 * 1 << 10, false},
+		{"10B", 10, false},
+		{"garbage", 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseByteSize(test.in)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseByteSize(%q): unexpected err %v\n", test.in, err)
+		}
+		if got != test.want {
+			t.Errorf("ParseByteSize(%q): got %d, want %d\n", test.in, got, test.want)
+		}
+	}
+}
+
+func TestMemoryCacheEvictsOverCapacity(t *testing.T) {
+
+	for _, policy := range []EvictionPolicy{LRU, LFU} {
+
+		cache := NewMemoryCache(10, policy)
+
+		k1, k2, k3 := NewCacheKey("k1"), NewCacheKey("k2"), NewCacheKey("k3")
+		v := bytes.NewBuffer([]byte("12345")) // 5 bytes each, capacity fits 2
+
+		cache.Put(k1, v, time.Time{})
+		cache.Put(k2, v, time.Time{})
+
+		// touch k1 so it is hotter/newer than k2 under every policy above
+		cache.Get(k1)
+
+		cache.Put(k3, v, time.Time{})
+
+		if status, _ := cache.IsPresent(k2); status == CacheStatusSuccess {
+			t.Errorf("policy %d: expected k2 to be evicted once over capacity\n", policy)
+		}
+		if status, _ := cache.IsPresent(k3); status != CacheStatusSuccess {
+			t.Errorf("policy %d: expected newly put k3 to be present\n", policy)
+		}
+
+		k1.Done()
+		k2.Done()
+		k3.Done()
+	}
+}
+
+func TestMemoryCacheTinyLFURejectsColdAdmission(t *testing.T) {
+
+	cache := NewMemoryCache(10, TinyLFU)
+
+	k1, k2, cold := NewCacheKey("k1"), NewCacheKey("k2"), NewCacheKey("cold")
+	v := bytes.NewBuffer([]byte("12345")) // 5 bytes each, capacity fits 2
+
+	cache.Put(k1, v, time.Time{})
+	cache.Put(k2, v, time.Time{})
+
+	// k1/k2 now have an access-frequency head start; a never-seen key
+	// should not be allowed to evict either of them on its first insert
+	cache.Put(cold, v, time.Time{})
+
+	if status, _ := cache.IsPresent(cold); status == CacheStatusSuccess {
+		t.Errorf("expected cold key to be rejected admission while cache is full of hotter keys\n")
+	}
+	if status, _ := cache.IsPresent(k1); status != CacheStatusSuccess {
+		t.Errorf("expected k1 to survive the rejected admission\n")
+	}
+	if status, _ := cache.IsPresent(k2); status != CacheStatusSuccess {
+		t.Errorf("expected k2 to survive the rejected admission\n")
+	}
+
+	k1.Done()
+	k2.Done()
+	cold.Done()
+}
+
+func TestMemoryCacheTinyLFUEventuallyAdmitsRepeatedCandidate(t *testing.T) {
+
+	cache := NewMemoryCache(5, TinyLFU)
+
+	a, b := NewCacheKey("a"), NewCacheKey("b")
+	v := bytes.NewBuffer([]byte("12345")) // 5 bytes, capacity fits exactly 1
+
+	cache.Put(a, v, time.Time{})
+
+	admitted := false
+	for i := 0; i < 1000 && !admitted; i++ {
+		cache.Put(b, v, time.Time{})
+		if status, _ := cache.IsPresent(b); status == CacheStatusSuccess {
+			admitted = true
+		}
+	}
+
+	if !admitted {
+		t.Errorf("expected a repeatedly requested key to eventually be admitted instead of being rejected forever\n")
+	}
+
+	a.Done()
+	b.Done()
+}
+
+func TestMemoryCacheTTLExpiry(t *testing.T) {
+
+	cache := NewMemoryCache(1<<20, LRU)
+
+	key := NewCacheKey("expiring")
+	val := bytes.NewBuffer([]byte("gone soon"))
+
+	cache.Put(key, val, time.Now().Add(-1*time.Second)) // already expired
+
+	if _, _, err := cache.Get(key); err == nil {
+		t.Errorf("expected expired key to miss on Get\n")
+	}
+
+	key.Done()
+}
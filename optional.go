@@ -0,0 +1,24 @@
+package mlcache
+
+import (
+	"bytes"
+	"time"
+)
+
+// BatchGetter is an optional interface a Cacher may implement to serve
+// many Get requests in a single round trip (e.g. Redis MGET). Cacher
+// implementations gain batch support purely by implementing this
+// interface, so existing Cacher implementations keep compiling unmodified.
+type BatchGetter interface {
+	GetMulti(keys []*CacheKey) ([]*bytes.Buffer, []time.Time, error)
+}
+
+// BatchCacher is an optional interface a Cacher may implement to service
+// many Put/Del requests in a single round trip (e.g. Redis pipelining).
+// It embeds BatchGetter, so an adapter implementing BatchCacher covers
+// batched reads and writes alike.
+type BatchCacher interface {
+	BatchGetter
+	PutMulti(keys []*CacheKey, vals []*bytes.Buffer, expires []time.Time) (CacheStatus, error)
+	DelMulti(keys []*CacheKey) (CacheStatus, error)
+}
@@ -0,0 +1,267 @@
+// Package v2 provides a generics-based multi-level cache that works with
+// typed values directly instead of *bytes.Buffer. Its Cacher interface
+// mirrors the v1 one (github.com/gptankit/mlcache) method for method, so
+// callers who can afford Go generics avoid the allocate-and-copy step of
+// marshalling into buffers. NewMultiLevelCache takes the caches as variadic
+// arguments and has no maxValSize limit and no options.
+package v2
+
+import (
+	"time"
+
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/errs"
+)
+
+// lCache is a doubly linked list of caches in the system
+type lCache[K comparable, V any] struct {
+	cur  Cacher[K, V]
+	prev *lCache[K, V]
+	next *lCache[K, V]
+}
+
+// cacher is multi-level cache object
+type cacher[K comparable, V any] struct {
+	// Head lCache pointer
+	l1Cache *lCache[K, V]
+	// Tail lCache pointer
+	lnCache *lCache[K, V]
+	// Current number of caches
+	numCaches uint8
+	// Read pattern to be used while Get
+	readPattern mlcache.ReadPattern
+	// Write pattern to be used while Put
+	writePattern mlcache.WritePattern
+	// Bounded worker pool that propagates WriteBack puts to upper levels
+	writeBackCh chan writeBackJob[K, V]
+}
+
+// writeBackJob carries one WriteBack propagation from l1Cache up through
+// the remaining cache levels
+type writeBackJob[K comparable, V any] struct {
+	this *lCache[K, V]
+	key  K
+	val  V
+	ttl  time.Time
+}
+
+const (
+	defaultWriteBackDelay   = 200 * time.Millisecond
+	defaultWriteBackWorkers = 16
+)
+
+// startWriteBack spins up a bounded pool of workers that drain writeBackCh,
+// propagating a WriteBack put to the upper cache levels after a short
+// delay. A fixed-size pool replaces a goroutine-per-write so a burst of
+// Puts queues instead of fanning out unbounded goroutines.
+func (ca *cacher[K, V]) startWriteBack() {
+
+	if ca.writePattern != mlcache.WriteBack {
+		return
+	}
+
+	ca.writeBackCh = make(chan writeBackJob[K, V], defaultWriteBackWorkers*4)
+
+	for i := 0; i < defaultWriteBackWorkers; i++ {
+		go ca.writeBackWorker()
+	}
+}
+
+func (ca *cacher[K, V]) writeBackWorker() {
+
+	for job := range ca.writeBackCh {
+		time.Sleep(defaultWriteBackDelay)
+
+		upper := job.this.next
+		for upper != nil {
+			status, err := upper.cur.Put(job.key, job.val, job.ttl)
+			if err != nil || status == mlcache.CacheStatusFailure {
+				break
+			}
+			upper = upper.next
+		}
+	}
+}
+
+// Cacher is an interface to be used by concrete cache implementation
+type Cacher[K comparable, V any] interface {
+	// Get returns the cache item, if present
+	Get(key K) (V, time.Time, error)
+	// Put adds/updates a cache item
+	Put(key K, val V, expires time.Time) (mlcache.CacheStatus, error)
+	// Del deletes the key from the cache
+	Del(key K) (mlcache.CacheStatus, error)
+	// IsPresent returns true if the key is present
+	IsPresent(key K) (mlcache.CacheStatus, error)
+	// Flush clears all keys from the cache
+	Flush() error
+}
+
+// NewMultiLevelCache creates a new mlcache object.
+// It expects 0 < num(caches) <= maxCaches and pre-defined read/write patterns to be passed in.
+func NewMultiLevelCache[K comparable, V any](readPattern mlcache.ReadPattern, writePattern mlcache.WritePattern, caches ...Cacher[K, V]) (Cacher[K, V], error) {
+
+	numCaches := uint8(len(caches))
+
+	if err := validate(numCaches, readPattern, writePattern); err != nil {
+		return nil, err
+	}
+
+	ci := uint8(0)
+	eCache := &lCache[K, V]{cur: caches[ci], prev: nil, next: nil}
+	sCache := eCache // save head
+	ci++
+	for ci < numCaches {
+		eCache.next = &lCache[K, V]{cur: caches[ci], prev: eCache, next: nil}
+		eCache = eCache.next
+		ci++
+	}
+
+	ca := &cacher[K, V]{
+		l1Cache:      sCache,
+		lnCache:      eCache,
+		numCaches:    numCaches,
+		readPattern:  readPattern,
+		writePattern: writePattern,
+	}
+
+	ca.startWriteBack()
+
+	return ca, nil
+}
+
+// Get executes a cache fetch given a key using pre-selected read pattern
+func (ca *cacher[K, V]) Get(key K) (V, time.Time, error) {
+
+	var zero V
+
+	switch ca.readPattern {
+	case mlcache.ReadThrough:
+		this := ca.l1Cache
+		for this != nil {
+			cache := this.cur
+			val, ttl, err := cache.Get(key) // lookup in a cache
+			if err == nil {                 // if found in higher level cache, sync populate all lower level caches and return from lowest level cache
+				lower := this.prev
+				for lower != nil {
+					status, err := lower.cur.Put(key, val, ttl)
+					if err != nil || status == mlcache.CacheStatusFailure {
+						return zero, time.Now().UTC(), err
+					}
+					lower = lower.prev
+				}
+				return val, ttl, nil
+			} else {
+				this = this.next
+			}
+		}
+
+	case mlcache.CacheAside:
+		this := ca.l1Cache
+		for this != nil {
+			cache := this.cur
+			val, ttl, err := cache.Get(key) // lookup in a cache
+			if err == nil {                 // if found in higher level cache, return first and async populate all lower level caches
+				go func() {
+					lower := this.prev
+					for lower != nil {
+						lower.cur.Put(key, val, ttl)
+						lower = lower.prev
+					}
+				}()
+				return val, ttl, nil
+			} else {
+				this = this.next
+			}
+		}
+	}
+
+	return zero, time.Now().UTC(), nil
+}
+
+// Put executes a cache add/update given a key, val and expiry time using pre-selected write pattern.
+// It expects an absolute value of time (and not duration).
+func (ca *cacher[K, V]) Put(key K, val V, ttl time.Time) (mlcache.CacheStatus, error) {
+
+	switch ca.writePattern {
+	case mlcache.WriteThrough:
+		this := ca.l1Cache
+		for this != nil {
+			cache := this.cur
+			cacheStatus, err := cache.Put(key, val, ttl) // put in all cache levels
+			if err != nil || cacheStatus == mlcache.CacheStatusFailure {
+				return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+			}
+			this = this.next
+		}
+		return mlcache.CacheStatusSuccess, nil
+
+	case mlcache.WriteAround:
+		this := ca.lnCache
+		cache := this.cur                            // get level n cache
+		cacheStatus, err := cache.Put(key, val, ttl) // put in a cache
+		if err != nil || cacheStatus == mlcache.CacheStatusFailure {
+			return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+		}
+		return mlcache.CacheStatusSuccess, nil
+
+	case mlcache.WriteBack:
+		this := ca.l1Cache
+		cache := this.cur                            // get level 1 cache
+		cacheStatus, err := cache.Put(key, val, ttl) // put in a cache
+		if err != nil || cacheStatus == mlcache.CacheStatusFailure {
+			return mlcache.CacheStatusFailure, errs.Build(err, mlcache.PutError)
+		}
+		// hand off to the bounded worker pool instead of spawning a
+		// goroutine per write, so that a burst of Puts queues rather
+		// than fanning out unbounded goroutines
+		ca.writeBackCh <- writeBackJob[K, V]{this: this, key: key, val: val, ttl: ttl}
+		return mlcache.CacheStatusSuccess, nil
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// Del removes a cache item from all caches
+func (ca *cacher[K, V]) Del(key K) (mlcache.CacheStatus, error) {
+
+	// deleting order -> level n to level 1
+	this := ca.lnCache
+	for this != nil {
+		cache := this.cur
+		cacheStatus, err := cache.Del(key) // delete from cache
+		if err != nil || cacheStatus == mlcache.CacheStatusFailure {
+			return mlcache.CacheStatusFailure, errs.Build(err, mlcache.DelError)
+		}
+		this = this.prev
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// IsPresent checks if a particular key exists or not.
+// It checks only in L1 cache assuming consistency between all cache levels.
+// All inconsistencies must be handled using Get/Put methods.
+func (ca *cacher[K, V]) IsPresent(key K) (mlcache.CacheStatus, error) {
+
+	this := ca.l1Cache
+	cache := this.cur                        // get level 1 cache
+	cacheStatus, err := cache.IsPresent(key) // check only in level 1 cache, assuming all caches are in sync
+	if err != nil || cacheStatus == mlcache.CacheStatusFailure {
+		return mlcache.CacheStatusFailure, errs.Build(err, mlcache.IsPresentError)
+	}
+
+	return mlcache.CacheStatusSuccess, nil
+}
+
+// Flush clears all items from all cache levels
+func (ca *cacher[K, V]) Flush() error {
+
+	this := ca.l1Cache
+	for this != nil {
+		cache := this.cur
+		go cache.Flush() // async flush
+		this = this.next
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package v2
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/errs"
+)
+
+type mockCacher[K comparable, V any] struct {
+	cache    map[K]V
+	cacheMtx *sync.Mutex
+}
+
+func NewMockCacher[K comparable, V any]() Cacher[K, V] {
+
+	cache := make(map[K]V)
+	cacher := &mockCacher[K, V]{
+		cache:    cache,
+		cacheMtx: &sync.Mutex{},
+	}
+
+	return cacher
+}
+
+func (c *mockCacher[K, V]) Get(key K) (V, time.Time, error) {
+
+	c.cacheMtx.Lock()
+	defer c.cacheMtx.Unlock()
+
+	if val, ok := c.cache[key]; ok {
+		return val, time.Now().UTC(), nil
+	}
+
+	var zero V
+	return zero, time.Now().UTC(), errs.New("Could not get item")
+}
+
+func (c *mockCacher[K, V]) Put(key K, val V, expires time.Time) (mlcache.CacheStatus, error) {
+
+	c.cacheMtx.Lock()
+	defer c.cacheMtx.Unlock()
+
+	c.cache[key] = val
+	return mlcache.CacheStatusSuccess, nil
+}
+
+func (c *mockCacher[K, V]) Del(key K) (mlcache.CacheStatus, error) {
+
+	c.cacheMtx.Lock()
+	defer c.cacheMtx.Unlock()
+
+	delete(c.cache, key)
+	return mlcache.CacheStatusSuccess, nil
+}
+
+func (c *mockCacher[K, V]) IsPresent(key K) (mlcache.CacheStatus, error) {
+
+	c.cacheMtx.Lock()
+	defer c.cacheMtx.Unlock()
+
+	if _, ok := c.cache[key]; ok {
+		return mlcache.CacheStatusSuccess, nil
+	}
+	return mlcache.CacheStatusFailure, nil
+}
+
+func (c *mockCacher[K, V]) Flush() error {
+
+	c.cacheMtx.Lock()
+	defer c.cacheMtx.Unlock()
+
+	c.cache = make(map[K]V)
+	return nil
+}
+
+type test struct {
+	id           uint8
+	readPattern  mlcache.ReadPattern
+	writePattern mlcache.WritePattern
+	ttl          time.Duration
+	cacheKey     string
+	cacheVal     string
+	wantEmpty    bool
+	wantInL1     bool
+	wantInL2     bool
+	wantInL3     bool
+	assert       func(string, string) bool
+}
+
+func TestCacher(t *testing.T) {
+
+	tests := createTestCases()
+
+	for _, test := range tests {
+
+		// multi-level cacher with 3 levels of cache
+		l1Cache, l2Cache, l3Cache := NewMockCacher[string, string](), NewMockCacher[string, string](), NewMockCacher[string, string]()
+		cacher, err := NewMultiLevelCache[string, string](test.readPattern, test.writePattern, l1Cache, l2Cache, l3Cache)
+		if err != nil {
+			t.Errorf("testid:%d: problem creating cacher\n", test.id)
+			t.FailNow()
+		}
+
+		// add to ml cache with selected write pattern
+		cacher.Put(test.cacheKey, test.cacheVal, time.Now().Add(test.ttl))
+
+		_, _, err = l1Cache.Get(test.cacheKey)
+		if test.wantInL1 == (err != nil) {
+			t.Errorf("testid:%d: wantInL1 %v did not succeed\n", test.id, test.wantInL1)
+			t.FailNow()
+		}
+		_, _, err = l2Cache.Get(test.cacheKey)
+		if test.wantInL2 == (err != nil) {
+			t.Errorf("testid:%d: wantInL2 %v did not succeed\n", test.id, test.wantInL2)
+			t.FailNow()
+		}
+		_, _, err = l3Cache.Get(test.cacheKey)
+		if test.wantInL3 == (err != nil) {
+			t.Errorf("testid:%d: wantInL3 %v did not succeed\n", test.id, test.wantInL3)
+			t.FailNow()
+		}
+
+		// get from ml cache with selected read pattern
+		val, _, err := cacher.Get(test.cacheKey)
+
+		if test.wantEmpty != (err != nil) {
+			t.Errorf("testid:%d: wantEmpty %v did not succeed\n", test.id, test.wantEmpty)
+			t.FailNow()
+		}
+
+		if err == nil && !test.assert(val, test.cacheVal) {
+			t.Errorf("testid:%d: want %s, got %s  \n", test.id, test.cacheVal, val)
+			t.FailNow()
+		}
+
+		cacher.Flush()
+	}
+}
+
+func TestCacherIsPresent(t *testing.T) {
+
+	l1Cache := NewMockCacher[string, string]()
+	cacher, err := NewMultiLevelCache[string, string](mlcache.ReadThrough, mlcache.WriteThrough, l1Cache)
+	if err != nil {
+		t.Fatalf("problem creating cacher: %v\n", err)
+	}
+
+	if status, err := cacher.IsPresent("missing"); err == nil || status != mlcache.CacheStatusFailure {
+		t.Errorf("expected a miss to report CacheStatusFailure with an error, got status=%v err=%v\n", status, err)
+	}
+
+	cacher.Put("present", "value", time.Now().Add(time.Minute))
+
+	if status, err := cacher.IsPresent("present"); err != nil || status != mlcache.CacheStatusSuccess {
+		t.Errorf("expected an existing key to report CacheStatusSuccess, got status=%v err=%v\n", status, err)
+	}
+}
+
+func BenchmarkCacher(b *testing.B) {
+
+	tests := createTestCases()
+
+	for _, test := range tests {
+
+		// multi-level cacher with 3 levels of cache
+		l1Cache, l2Cache, l3Cache := NewMockCacher[string, string](), NewMockCacher[string, string](), NewMockCacher[string, string]()
+		cacher, err := NewMultiLevelCache[string, string](test.readPattern, test.writePattern, l1Cache, l2Cache, l3Cache)
+		if err != nil {
+			b.Errorf("testid:%d: problem creating cacher\n", test.id)
+			b.FailNow()
+		}
+
+		testid := strconv.Itoa(int(test.id))
+		b.Run("W"+testid, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// add to ml cache with selected write pattern
+				cacher.Put(test.cacheKey, test.cacheVal, time.Now().Add(test.ttl))
+			}
+		})
+
+		b.Run("R"+testid, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// get from ml cache with selected read pattern
+				cacher.Get(test.cacheKey)
+			}
+		})
+
+		cacher.Flush()
+	}
+}
+
+func createTestCases() []test {
+
+	return []test{
+		{
+			id:           1,
+			readPattern:  mlcache.ReadThrough,
+			writePattern: mlcache.WriteThrough,
+			ttl:          5 * time.Second,
+			cacheKey:     "metamorphosis",
+			cacheVal:     "franzkafka",
+			wantEmpty:    false,
+			wantInL1:     true,
+			wantInL2:     true,
+			wantInL3:     true,
+			assert: func(s1 string, s2 string) bool {
+				return s1 == s2
+			},
+		},
+		{
+			id:           2,
+			readPattern:  mlcache.CacheAside,
+			writePattern: mlcache.WriteThrough,
+			ttl:          5 * time.Second,
+			cacheKey:     "thecatcherintherye",
+			cacheVal:     "jdsalinger",
+			wantEmpty:    false,
+			wantInL1:     true,
+			wantInL2:     true,
+			wantInL3:     true,
+			assert: func(s1 string, s2 string) bool {
+				return s1 == s2
+			},
+		},
+		{
+			id:           3,
+			readPattern:  mlcache.ReadThrough,
+			writePattern: mlcache.WriteAround,
+			ttl:          5 * time.Second,
+			cacheKey:     "cosmos",
+			cacheVal:     "carlsagan",
+			wantEmpty:    false,
+			wantInL1:     false,
+			wantInL2:     false,
+			wantInL3:     true,
+			assert: func(s1 string, s2 string) bool {
+				return s1 == s2
+			},
+		},
+		{
+			id:           4,
+			readPattern:  mlcache.CacheAside,
+			writePattern: mlcache.WriteAround,
+			ttl:          5 * time.Second,
+			cacheKey:     "siddhartha",
+			cacheVal:     "hermannhesse",
+			wantEmpty:    false,
+			wantInL1:     false,
+			wantInL2:     false,
+			wantInL3:     true,
+			assert: func(s1 string, s2 string) bool {
+				return s1 == s2
+			},
+		},
+		{
+			id:           5,
+			readPattern:  mlcache.ReadThrough,
+			writePattern: mlcache.WriteBack,
+			ttl:          5 * time.Second,
+			cacheKey:     "thesirensoftitan",
+			cacheVal:     "kurtvonnegut",
+			wantEmpty:    false,
+			wantInL1:     true,
+			wantInL2:     false,
+			wantInL3:     false,
+			assert: func(s1 string, s2 string) bool {
+				return s1 == s2
+			},
+		},
+		{
+			id:           6,
+			readPattern:  mlcache.CacheAside,
+			writePattern: mlcache.WriteBack,
+			ttl:          5 * time.Second,
+			cacheKey:     "thecolorpurple",
+			cacheVal:     "alicewalker",
+			wantEmpty:    false,
+			wantInL1:     true,
+			wantInL2:     false,
+			wantInL3:     false,
+			assert: func(s1 string, s2 string) bool {
+				return s1 == s2
+			},
+		},
+	}
+}
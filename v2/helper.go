@@ -0,0 +1,31 @@
+package v2
+
+import (
+	"github.com/gptankit/mlcache"
+	"github.com/gptankit/mlcache/errs"
+)
+
+// Max number of cache levels allowed in the system
+const maxCaches uint8 = 5
+
+// validate checks if the input parameters are within allowed limits
+func validate(numCaches uint8, readPattern mlcache.ReadPattern, writePattern mlcache.WritePattern) error {
+
+	if numCaches == 0 { // if called with no cache parameters
+		return errs.New(mlcache.NoWorkableCacheFound)
+	} else if numCaches > maxCaches { // if called with more than maxCache limit
+		return errs.New(mlcache.MaxCacheLevelExceeded)
+	}
+
+	// if invalid readPattern selected
+	if readPattern != mlcache.ReadThrough && readPattern != mlcache.CacheAside {
+		return errs.New(mlcache.InvalidReadPattern)
+	}
+
+	// if invalid writePattern selected
+	if writePattern != mlcache.WriteThrough && writePattern != mlcache.WriteAround && writePattern != mlcache.WriteBack {
+		return errs.New(mlcache.InvalidWritePattern)
+	}
+
+	return nil
+}
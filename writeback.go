@@ -0,0 +1,283 @@
+package mlcache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gptankit/mlcache/errs"
+)
+
+// Default settings for the WriteBack write pattern. These can be overridden
+// per-cacher with WithWriteBackDelay and WithWriteBackWorkers.
+const (
+	defaultWriteBackDelay   = 200 * time.Millisecond
+	defaultWriteBackWorkers = 16
+)
+
+// WithWriteBackDelay overrides how long a WriteBack Put waits before
+// propagating to upper level caches. Defaults to 200ms.
+func WithWriteBackDelay(delay time.Duration) Option {
+	return func(ca *cacher) {
+		ca.writeBackDelay = delay
+	}
+}
+
+// WithWriteBackWorkers bounds the number of goroutines that propagate
+// WriteBack writes to upper levels, so a burst of Puts queues instead of
+// spawning one goroutine per write. Defaults to 16.
+func WithWriteBackWorkers(workers int) Option {
+	return func(ca *cacher) {
+		ca.writeBackWorkers = workers
+	}
+}
+
+// WithWriteBackJournal wires a durable write-ahead log into the WriteBack
+// pattern, so a crash between the L1 Put and the upper-level Puts can be
+// recovered from on the next startup instead of silently losing data.
+func WithWriteBackJournal(journal WriteBackJournal) Option {
+	return func(ca *cacher) {
+		ca.writeBackJournal = journal
+	}
+}
+
+// WriteBackJournalEntry is a single pending (not yet fully propagated)
+// WriteBack write.
+type WriteBackJournalEntry struct {
+	ID      string
+	Key     string
+	Val     *bytes.Buffer
+	Expires time.Time
+}
+
+// WriteBackJournal durably records pending WriteBack propagations. Append
+// is called once a value has landed in L1 but before upper levels have
+// been written; Ack is called once every upper level Put has succeeded.
+// Replay is called once at startup to re-drive any entry that was
+// appended but never acked, i.e. a write that didn't survive a crash.
+type WriteBackJournal interface {
+	Append(key string, val *bytes.Buffer, expires time.Time) (id string, err error)
+	Ack(id string) error
+	Replay(fn func(WriteBackJournalEntry)) error
+	Close() error
+}
+
+// writeBackJob is a pending upper-level propagation handed to a worker
+type writeBackJob struct {
+	this      *lCache
+	key       *CacheKey
+	val       *bytes.Buffer
+	ttl       time.Time
+	journalID string
+}
+
+// startWriteBack wires up the bounded worker pool (and, if configured, the
+// write-ahead journal replay) backing the WriteBack write pattern. It is a
+// no-op unless ca.writePattern is WriteBack.
+func (ca *cacher) startWriteBack() {
+
+	if ca.writePattern != WriteBack {
+		return
+	}
+
+	if ca.writeBackDelay == 0 {
+		ca.writeBackDelay = defaultWriteBackDelay
+	}
+	if ca.writeBackWorkers == 0 {
+		ca.writeBackWorkers = defaultWriteBackWorkers
+	}
+
+	ca.writeBackCh = make(chan writeBackJob, ca.writeBackWorkers*4)
+
+	for i := 0; i < ca.writeBackWorkers; i++ {
+		go ca.writeBackWorker()
+	}
+
+	if ca.writeBackJournal != nil {
+		ca.writeBackJournal.Replay(func(entry WriteBackJournalEntry) {
+			key := NewCacheKey(entry.Key)
+			ca.writeBackCh <- writeBackJob{this: ca.l1Cache, key: key, val: entry.Val, ttl: entry.Expires, journalID: entry.ID}
+		})
+	}
+}
+
+// writeBackWorker drains write-back jobs, propagating each to every cache
+// level above L1 after the configured delay
+func (ca *cacher) writeBackWorker() {
+
+	for job := range ca.writeBackCh {
+		time.Sleep(ca.writeBackDelay)
+
+		propagated := true
+		upper := job.this.next
+		for upper != nil {
+			status, err := upper.cur.Put(job.key, job.val, job.ttl)
+			if err != nil || status == CacheStatusFailure {
+				propagated = false
+				break
+			}
+			upper = upper.next
+		}
+
+		if propagated && ca.writeBackJournal != nil && job.journalID != "" {
+			ca.writeBackJournal.Ack(job.journalID)
+		}
+	}
+}
+
+// journalRecord is the on-disk JSON form of a single journal line
+type journalRecord struct {
+	Op      string    `json:"op"`
+	ID      string    `json:"id"`
+	Key     string    `json:"key,omitempty"`
+	Val     string    `json:"val,omitempty"` // base64, omitted for ack records
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// fileWriteBackJournal is a WriteBackJournal backed by an append-only
+// segment file. Each Append/Ack is fsynced before returning; once every
+// appended entry has been acked the file is truncated back to empty.
+type fileWriteBackJournal struct {
+	mtx     sync.Mutex
+	f       *os.File
+	pending map[string]WriteBackJournalEntry
+}
+
+// NewFileWriteBackJournal opens (or creates) the journal segment file at
+// path, replaying any entries left on disk from a previous crash into its
+// in-memory pending set for Replay to hand back to the caller.
+func NewFileWriteBackJournal(path string) (*fileWriteBackJournal, error) {
+
+	pending := make(map[string]WriteBackJournalEntry)
+
+	if data, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line == "" {
+				continue
+			}
+
+			var rec journalRecord
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				continue // tolerate a torn trailing write from a crash mid-append
+			}
+
+			switch rec.Op {
+			case "append":
+				entry := WriteBackJournalEntry{ID: rec.ID, Key: rec.Key, Expires: rec.Expires}
+				if rec.Val != "" {
+					if raw, err := base64.StdEncoding.DecodeString(rec.Val); err == nil {
+						entry.Val = bytes.NewBuffer(raw)
+					}
+				}
+				pending[rec.ID] = entry
+			case "ack":
+				delete(pending, rec.ID)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, errs.Build(err, FlushError)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileWriteBackJournal{f: f, pending: pending}, nil
+}
+
+// Append durably records a pending write and returns its id
+func (j *fileWriteBackJournal) Append(key string, val *bytes.Buffer, expires time.Time) (string, error) {
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	id := newInstanceID()
+
+	rec := journalRecord{Op: "append", ID: id, Key: key, Expires: expires}
+	if val != nil {
+		rec.Val = base64.StdEncoding.EncodeToString(val.Bytes())
+	}
+
+	if err := j.writeRecordLocked(rec); err != nil {
+		return "", err
+	}
+
+	entry := WriteBackJournalEntry{ID: id, Key: key, Expires: expires}
+	if val != nil {
+		entry.Val = bytes.NewBuffer(val.Bytes())
+	}
+	j.pending[id] = entry
+
+	return id, nil
+}
+
+// Ack marks a previously appended entry as fully propagated, compacting
+// the segment file once nothing remains pending
+func (j *fileWriteBackJournal) Ack(id string) error {
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	if err := j.writeRecordLocked(journalRecord{Op: "ack", ID: id}); err != nil {
+		return err
+	}
+	delete(j.pending, id)
+
+	if len(j.pending) == 0 {
+		return j.compactLocked()
+	}
+
+	return nil
+}
+
+// Replay calls fn for every entry that was appended but never acked
+func (j *fileWriteBackJournal) Replay(fn func(WriteBackJournalEntry)) error {
+
+	j.mtx.Lock()
+	entries := make([]WriteBackJournalEntry, 0, len(j.pending))
+	for _, entry := range j.pending {
+		entries = append(entries, entry)
+	}
+	j.mtx.Unlock()
+
+	for _, entry := range entries {
+		fn(entry)
+	}
+
+	return nil
+}
+
+// Close releases the journal's underlying file handle
+func (j *fileWriteBackJournal) Close() error {
+	return j.f.Close()
+}
+
+func (j *fileWriteBackJournal) writeRecordLocked(rec journalRecord) error {
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	if _, err := j.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return j.f.Sync()
+}
+
+func (j *fileWriteBackJournal) compactLocked() error {
+
+	if err := j.f.Truncate(0); err != nil {
+		return err
+	}
+
+	_, err := j.f.Seek(0, io.SeekStart)
+	return err
+}
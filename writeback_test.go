@@ -0,0 +1,98 @@
+package mlcache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingCacher wraps a mockCacher and fails every Put until unblocked, so
+// a WriteBack propagation can be made to look like it never completed
+// (e.g. a crash between the L1 Put and the upper-level Put).
+type failingCacher struct {
+	*mockCacher
+	failing int32
+}
+
+func newFailingCacher() *failingCacher {
+	return &failingCacher{mockCacher: NewMockCacher().(*mockCacher), failing: 1}
+}
+
+func (c *failingCacher) Put(key *CacheKey, val *bytes.Buffer, expires time.Time) (CacheStatus, error) {
+	if atomic.LoadInt32(&c.failing) != 0 {
+		return CacheStatusFailure, nil
+	}
+	return c.mockCacher.Put(key, val, expires)
+}
+
+func (c *failingCacher) unblock() {
+	atomic.StoreInt32(&c.failing, 0)
+}
+
+func TestWriteBackJournalReplaysUnackedEntryAfterCrash(t *testing.T) {
+
+	journalPath := filepath.Join(t.TempDir(), "writeback.journal")
+
+	journal, err := NewFileWriteBackJournal(journalPath)
+	if err != nil {
+		t.Fatalf("problem creating journal: %v\n", err)
+	}
+
+	l1 := NewMockCacher()
+	l2 := newFailingCacher() // upper level that never accepts the propagated Put, simulating a crashed/unreachable node
+
+	ca, err := NewMultiLevelCache(ReadThrough, WriteBack, 100, []Cacher{l1, l2},
+		WithWriteBackJournal(journal),
+		WithWriteBackDelay(5*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("problem creating cacher: %v\n", err)
+	}
+
+	key := NewCacheKey("journaled")
+	val := bytes.NewBuffer([]byte("durable"))
+
+	ca.Put(key, val, time.Now().Add(time.Minute))
+
+	// give the worker pool a chance to attempt (and fail) the propagation
+	time.Sleep(50 * time.Millisecond)
+
+	if status, _ := l2.IsPresent(key); status == CacheStatusSuccess {
+		t.Fatalf("test setup broken: l2 accepted the Put, nothing left to replay\n")
+	}
+
+	journal.Close() // simulate the process crashing without ever acking the entry
+
+	// "restart": reopen the journal from disk and a fresh cacher pointed
+	// at the same (now healthy) upper level
+	replayedJournal, err := NewFileWriteBackJournal(journalPath)
+	if err != nil {
+		t.Fatalf("problem reopening journal: %v\n", err)
+	}
+
+	l2.unblock()
+
+	ca2, err := NewMultiLevelCache(ReadThrough, WriteBack, 100, []Cacher{l1, l2},
+		WithWriteBackJournal(replayedJournal),
+		WithWriteBackDelay(5*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("problem creating recovery cacher: %v\n", err)
+	}
+	defer ca2.(*cacher).Close()
+
+	time.Sleep(50 * time.Millisecond) // let the replayed entry propagate
+
+	if status, _ := l2.IsPresent(key); status != CacheStatusSuccess {
+		t.Errorf("expected the unacked journal entry to be replayed into l2 on restart\n")
+	}
+
+	if data, err := os.ReadFile(journalPath); err != nil || len(data) != 0 {
+		t.Errorf("expected the journal to be compacted back to empty once the replayed entry is acked, got %d bytes (err=%v)\n", len(data), err)
+	}
+
+	key.Done()
+}